@@ -0,0 +1,113 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	"os"
+	"time"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+const (
+	DefaultOperatorNamespace             = "hana-mapping-operator-system"
+	DefaultBTPOperatorConfigmapNamespace = "kyma-system"
+	DefaultBTPOperatorConfigmapName      = "sap-btp-operator-config"
+)
+
+var defaultFeatureGates = map[string]bool{
+	FeatureNamespaceController: false,
+	FeatureHANAMappingSet:      true,
+}
+
+// KnownFeature reports whether feature is a feature gate of the operator.
+func KnownFeature(feature string) bool {
+	_, ok := defaultFeatureGates[feature]
+	return ok
+}
+
+// SetDefaults sets the defaults of all unset fields. The operator namespace
+// defaults to the namespace of the pod.
+func SetDefaults(c *OperatorConfiguration) {
+	c.APIVersion = GroupVersion.String()
+	c.Kind = "OperatorConfiguration"
+
+	if len(c.Defaults.BTPOperatorConfigmap.Namespace) == 0 {
+		c.Defaults.BTPOperatorConfigmap.Namespace = DefaultBTPOperatorConfigmapNamespace
+	}
+	if len(c.Defaults.BTPOperatorConfigmap.Name) == 0 {
+		c.Defaults.BTPOperatorConfigmap.Name = DefaultBTPOperatorConfigmapName
+	}
+	if len(c.Defaults.ClusterIDSource) == 0 {
+		c.Defaults.ClusterIDSource = "configmap"
+	}
+	if len(c.Defaults.ClusterIDConfigMapKey) == 0 {
+		c.Defaults.ClusterIDConfigMapKey = "CLUSTER_ID"
+	}
+
+	if c.Resync.InventoryRefreshInterval.Duration == 0 {
+		c.Resync.InventoryRefreshInterval.Duration = 5 * time.Minute
+	}
+	if c.Resync.ClusterIDMigrationInterval.Duration == 0 {
+		c.Resync.ClusterIDMigrationInterval.Duration = 5 * time.Minute
+	}
+	if c.Resync.ClusterIDMigrationParallelism == 0 {
+		c.Resync.ClusterIDMigrationParallelism = 5
+	}
+	if c.Resync.CredentialsRevalidationInterval.Duration == 0 {
+		c.Resync.CredentialsRevalidationInterval.Duration = 10 * time.Minute
+	}
+	if c.Resync.MappingResyncInterval.Duration == 0 {
+		c.Resync.MappingResyncInterval.Duration = 10 * time.Minute
+	}
+	if c.Resync.ObjectCacheTTL.Duration == 0 {
+		c.Resync.ObjectCacheTTL.Duration = 30 * time.Second
+	}
+
+	if c.Inventory.QPS == 0 {
+		c.Inventory.QPS = 5
+	}
+	if c.Inventory.Burst == 0 {
+		c.Inventory.Burst = 10
+	}
+	if c.Inventory.FailureThreshold == 0 {
+		c.Inventory.FailureThreshold = 5
+	}
+	if c.Inventory.CircuitCooldown.Duration == 0 {
+		c.Inventory.CircuitCooldown.Duration = time.Minute
+	}
+
+	if c.Controller.MaxConcurrentReconciles == 0 {
+		c.Controller.MaxConcurrentReconciles = 1
+	}
+	if len(c.Controller.OperatorNamespace) == 0 {
+		c.Controller.OperatorNamespace = os.Getenv("POD_NAMESPACE")
+	}
+	if len(c.Controller.OperatorNamespace) == 0 {
+		c.Controller.OperatorNamespace = DefaultOperatorNamespace
+	}
+
+	if len(c.GarbageCollection.DeletionPolicy) == 0 {
+		c.GarbageCollection.DeletionPolicy = hanav1.DeletionPolicyDelete
+	}
+
+	if len(c.HTTP.MetricsBindAddress) == 0 {
+		c.HTTP.MetricsBindAddress = ":8080"
+	}
+	if len(c.HTTP.HealthProbeBindAddress) == 0 {
+		c.HTTP.HealthProbeBindAddress = ":8081"
+	}
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+const (
+	// FeatureNamespaceController creates HANAMappings for annotated namespaces.
+	FeatureNamespaceController = "NamespaceController"
+	// FeatureHANAMappingSet enables the HANAMappingSet controller.
+	FeatureHANAMappingSet = "HANAMappingSet"
+)
+
+// OperatorConfiguration is the configuration file of the operator. Flags
+// which are set explicitly take precedence over the file.
+type OperatorConfiguration struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// +optional
+	Defaults Defaults `json:"defaults,omitempty"`
+	// +optional
+	Resync Resync `json:"resync,omitempty"`
+	// +optional
+	Inventory Inventory `json:"inventory,omitempty"`
+	// +optional
+	Controller Controller `json:"controller,omitempty"`
+	// +optional
+	GarbageCollection GarbageCollection `json:"garbageCollection,omitempty"`
+	// FeatureGates enable or disable optional controllers by name.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// +optional
+	HTTP HTTP `json:"http,omitempty"`
+}
+
+// Defaults apply to HANAMappings which do not set the respective field.
+type Defaults struct {
+	// BTPOperatorConfigmap is read for the cluster ID, defaults to kyma-system/sap-btp-operator-config.
+	// +optional
+	BTPOperatorConfigmap hanav1.NamespacedName `json:"btpOperatorConfigmap,omitempty"`
+	// ClusterID is the cluster ID used as primary ID on kubernetes.
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+	// ClusterIDSource is either configmap or kube-system, defaults to configmap.
+	// +optional
+	ClusterIDSource string `json:"clusterIDSource,omitempty"`
+	// ClusterIDConfigMapKey is the key of the cluster ID in the BTP operator config map.
+	// +optional
+	ClusterIDConfigMapKey string `json:"clusterIDConfigMapKey,omitempty"`
+	// NamespaceAdminAPIAccessSecret is referenced by the HANAMappings of
+	// annotated namespaces which do not annotate credentials.
+	// +optional
+	NamespaceAdminAPIAccessSecret *hanav1.NamespacedName `json:"namespaceAdminAPIAccessSecret,omitempty"`
+}
+
+type Resync struct {
+	// InventoryRefreshInterval is the refresh interval of HANAInstanceInventories which do not set one.
+	// +optional
+	InventoryRefreshInterval metav1.Duration `json:"inventoryRefreshInterval,omitempty"`
+	// ClusterIDMigrationInterval is how often the cluster ID of the HANAMappings is checked.
+	// +optional
+	ClusterIDMigrationInterval metav1.Duration `json:"clusterIDMigrationInterval,omitempty"`
+	// ClusterIDMigrationParallelism is the maximum number of HANAMappings migrated concurrently.
+	// +optional
+	ClusterIDMigrationParallelism int `json:"clusterIDMigrationParallelism,omitempty"`
+	// CredentialsRevalidationInterval is how often ready HANAAdminCredentials
+	// fetch a token again. Negative values disable the revalidation.
+	// +optional
+	CredentialsRevalidationInterval metav1.Duration `json:"credentialsRevalidationInterval,omitempty"`
+	// MappingResyncInterval is how often synced HANAMappings are compared with
+	// the inventory again to correct drift. Negative values disable the resync.
+	// +optional
+	MappingResyncInterval metav1.Duration `json:"mappingResyncInterval,omitempty"`
+	// ObjectCacheTTL is how long secrets and config maps read by the operator are cached.
+	// +optional
+	ObjectCacheTTL metav1.Duration `json:"objectCacheTTL,omitempty"`
+}
+
+// Inventory configures the requests to the inventory API. It is reloaded
+// without a restart.
+type Inventory struct {
+	// QPS is the maximum number of requests per second per admin API access
+	// binding. A value < 0 disables rate limiting.
+	// +optional
+	QPS float64 `json:"qps,omitempty"`
+	// +optional
+	Burst int `json:"burst,omitempty"`
+	// FailureThreshold is the number of consecutive failures after which the
+	// circuit breaker opens. A value < 0 disables the circuit breaker.
+	// +optional
+	FailureThreshold int `json:"failureThreshold,omitempty"`
+	// +optional
+	CircuitCooldown metav1.Duration `json:"circuitCooldown,omitempty"`
+}
+
+type Controller struct {
+	// +optional
+	MaxConcurrentReconciles int `json:"maxConcurrentReconciles,omitempty"`
+	// OperatorNamespace is the only namespace ClusterHANAMappings read admin API access secrets from.
+	// +optional
+	OperatorNamespace string `json:"operatorNamespace,omitempty"`
+	// WatchNamespaces restricts the operator to the given namespaces. All
+	// namespaces are watched if it is empty.
+	// +listType=set
+	// +optional
+	WatchNamespaces []string `json:"watchNamespaces,omitempty"`
+	// +optional
+	LeaderElect bool `json:"leaderElect,omitempty"`
+}
+
+type GarbageCollection struct {
+	// DeletionPolicy applies to HANAMappings which do not set one. With
+	// Orphan the mapping is kept in the inventory when the HANAMapping is
+	// deleted. Defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+}
+
+type HTTP struct {
+	// +optional
+	MetricsBindAddress string `json:"metricsBindAddress,omitempty"`
+	// +optional
+	HealthProbeBindAddress string `json:"healthProbeBindAddress,omitempty"`
+	// +optional
+	SecureMetrics bool `json:"secureMetrics,omitempty"`
+	// EnableHTTP2 enables HTTP/2 for the metrics and webhook servers.
+	// +optional
+	EnableHTTP2 bool `json:"enableHTTP2,omitempty"`
+}
+
+// FeatureEnabled reports whether the feature gate is enabled.
+func (c *OperatorConfiguration) FeatureEnabled(feature string) bool {
+	if enabled, ok := c.FeatureGates[feature]; ok {
+		return enabled
+	}
+	return defaultFeatureGates[feature]
+}
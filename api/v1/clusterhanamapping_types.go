@@ -0,0 +1,78 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterHANAMappingSpec defines the desired state of ClusterHANAMapping
+type ClusterHANAMappingSpec struct {
+	// +optional
+	BTPOperatorConfigmap NamespacedName `json:"btpOperatorConfigmap,omitempty"`
+	// AdminAPIAccessSecret is the name of the admin API access secret in the namespace of the operator.
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	AdminAPIAccessSecret string `json:"adminAPIAccessSecret"`
+	// +required
+	ServiceInstanceID string `json:"serviceInstanceID"`
+	// TargetNamespaces are the namespaces the service instance is mapped into.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +required
+	TargetNamespaces []string `json:"targetNamespaces"`
+}
+
+// ClusterHANAMappingStatus defines the observed state of ClusterHANAMapping
+type ClusterHANAMappingStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// +optional
+	MappingIDs []MappingID `json:"mappingIDs,omitempty"`
+	// Operations journal the mappings created by the current reconciliation
+	// until they are recorded in MappingIDs, so that they are not leaked.
+	// +optional
+	Operations []InventoryOperation `json:"operations,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:scope=Cluster
+//+kubebuilder:printcolumn:name="Service Instance ID",type="string",JSONPath=`.spec.serviceInstanceID`,description="Service Instance ID"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=='Ready')].status`,description="Ready"
+
+// ClusterHANAMapping is the Schema for the clusterhanamappings API
+type ClusterHANAMapping struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterHANAMappingSpec   `json:"spec,omitempty"`
+	Status ClusterHANAMappingStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ClusterHANAMappingList contains a list of ClusterHANAMapping
+type ClusterHANAMappingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterHANAMapping `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterHANAMapping{}, &ClusterHANAMappingList{})
+}
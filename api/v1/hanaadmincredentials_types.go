@@ -0,0 +1,81 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HANAAdminCredentialsSpec defines the desired state of HANAAdminCredentials
+// +kubebuilder:validation:XValidation:rule="has(self.secretRef) != has(self.serviceBindingRef)",message="exactly one of secretRef and serviceBindingRef must be set"
+type HANAAdminCredentialsSpec struct {
+	// SecretRef references a secret holding the admin API access binding in the namespace of the credentials.
+	// +optional
+	SecretRef *LocalObjectReference `json:"secretRef,omitempty"`
+	// ServiceBindingRef references a BTP operator ServiceBinding of the admin API access plan in the namespace of the credentials.
+	// +optional
+	ServiceBindingRef *LocalObjectReference `json:"serviceBindingRef,omitempty"`
+	// CABundle holds additional PEM encoded CA certificates trusted for the admin API.
+	// +optional
+	CABundle string `json:"caBundle,omitempty"`
+	// ProxyURL is the URL of an HTTP proxy the admin API is called through.
+	// +optional
+	ProxyURL string `json:"proxyURL,omitempty"`
+}
+
+// HANAAdminCredentialsStatus defines the observed state of HANAAdminCredentials
+type HANAAdminCredentialsStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// SecretName is the name of the secret the binding was last read from.
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+}
+
+type LocalObjectReference struct {
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:resource:shortName=hanaadmincreds
+//+kubebuilder:printcolumn:name="Secret",type="string",JSONPath=`.status.secretName`,description="Secret"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=='Ready')].status`,description="Ready"
+
+// HANAAdminCredentials is the Schema for the hanaadmincredentials API
+type HANAAdminCredentials struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HANAAdminCredentialsSpec   `json:"spec,omitempty"`
+	Status HANAAdminCredentialsStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HANAAdminCredentialsList contains a list of HANAAdminCredentials
+type HANAAdminCredentialsList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HANAAdminCredentials `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HANAAdminCredentials{}, &HANAAdminCredentialsList{})
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HANAInstanceInventorySpec defines the desired state of HANAInstanceInventory
+// +kubebuilder:validation:XValidation:rule="has(self.adminAPIAccessSecret) != has(self.adminCredentials)",message="exactly one of adminAPIAccessSecret and adminCredentials must be set"
+type HANAInstanceInventorySpec struct {
+	// +optional
+	AdminAPIAccessSecret *NamespacedName `json:"adminAPIAccessSecret,omitempty"`
+	// AdminCredentials is the name of a HANAAdminCredentials in the namespace of the inventory.
+	// +optional
+	AdminCredentials string `json:"adminCredentials,omitempty"`
+	// +required
+	ServiceInstanceID string `json:"serviceInstanceID"`
+	// RefreshInterval is the interval in which the mappings are listed. Defaults to 5m.
+	// +optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// HANAInstanceInventoryStatus defines the observed state of HANAInstanceInventory
+type HANAInstanceInventoryStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// LastSyncTime is the time the mappings were last listed.
+	// +optional
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// Mappings are all mappings of the service instance, including those of other clusters and Cloud Foundry.
+	// +optional
+	Mappings []InventoryMapping `json:"mappings,omitempty"`
+}
+
+type InventoryMapping struct {
+	// +required
+	Platform string `json:"platform"`
+	// +required
+	PrimaryID string `json:"primaryID"`
+	// +required
+	SecondaryID string `json:"secondaryID"`
+	// +optional
+	IsDefault bool `json:"isDefault,omitempty"`
+	// Owned is set if the mapping is managed by a HANAMapping or ClusterHANAMapping of this cluster.
+	// +optional
+	Owned bool `json:"owned,omitempty"`
+	// OwnedBy names the HANAMapping as namespace/name or the ClusterHANAMapping as name owning the mapping.
+	// +optional
+	OwnedBy string `json:"ownedBy,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Service Instance ID",type="string",JSONPath=`.spec.serviceInstanceID`,description="Service Instance ID"
+//+kubebuilder:printcolumn:name="Last Sync",type="date",JSONPath=`.status.lastSyncTime`,description="Last Sync"
+//+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=='Ready')].status`,description="Ready"
+
+// HANAInstanceInventory is the Schema for the hanainstanceinventories API
+type HANAInstanceInventory struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HANAInstanceInventorySpec   `json:"spec,omitempty"`
+	Status HANAInstanceInventoryStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HANAInstanceInventoryList contains a list of HANAInstanceInventory
+type HANAInstanceInventoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HANAInstanceInventory `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HANAInstanceInventory{}, &HANAInstanceInventoryList{})
+}
@@ -28,12 +28,28 @@ type HANAMappingSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
-	// +required
-	BTPOperatorConfigmap NamespacedName `json:"btpOperatorConfigmap"`
-	// +required
-	AdminAPIAccessSecret NamespacedName `json:"adminAPIAccessSecret"`
+	// +optional
+	BTPOperatorConfigmap NamespacedName `json:"btpOperatorConfigmap,omitempty"`
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="size(self.namespace) > 0 && size(self.name) > 0",message="adminAPIAccessSecret must reference a secret by namespace and name"
+	AdminAPIAccessSecret *NamespacedName `json:"adminAPIAccessSecret,omitempty"`
+	// AdminCredentials is the name of a HANAAdminCredentials in the namespace of the mapping.
+	// +optional
+	// +kubebuilder:validation:XValidation:rule="size(self) > 0",message="adminCredentials must not be empty"
+	AdminCredentials string `json:"adminCredentials,omitempty"`
 	// +required
 	Mapping Mapping `json:"mapping"`
+	// DeletionPolicy decides whether the mapping is deleted together with the HANAMapping, defaults to Delete.
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// AllowRemap allows to change the service instance ID once the mapping is created.
+	// +optional
+	AllowRemap bool `json:"allowRemap,omitempty"`
+	// ClusterID overrides the cluster ID used as primary ID on kubernetes.
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
 }
 
 // HANAMappingStatus defines the observed state of HANAMapping
@@ -45,6 +61,16 @@ type HANAMappingStatus struct {
 	Conditions []metav1.Condition `json:"conditions"`
 	// +optional
 	MappingID *MappingID `json:"mappingID,omitempty"`
+	// PendingMappingID is the mapping which replaces MappingID while the mapping is remapped.
+	// +optional
+	PendingMappingID *MappingID `json:"pendingMappingID,omitempty"`
+	// Operations journal the inventory calls of the current reconciliation, so that
+	// mappings created before a crash are not leaked.
+	// +optional
+	Operations []InventoryOperation `json:"operations,omitempty"`
+	// ClusterID is the cluster ID the mapping was created with on kubernetes.
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -52,6 +78,19 @@ type HANAMappingStatus struct {
 //+kubebuilder:printcolumn:name="Service Instance ID",type="string",JSONPath=`.spec.mapping.serviceInstanceID`,description="Service Instance ID"
 //+kubebuilder:printcolumn:name="Target Namespace",type="string",JSONPath=`.spec.mapping.targetNamespace`,description="Target Namespace"
 //+kubebuilder:printcolumn:name="Ready",type="string",JSONPath=`.status.conditions[?(@.type=='Ready')].status`,description="Ready"
+//+kubebuilder:printcolumn:name="Platform",type="string",JSONPath=`.spec.mapping.platform`,description="Platform",priority=1
+//+kubebuilder:printcolumn:name="Cluster ID",type="string",JSONPath=`.status.clusterID`,description="Cluster ID",priority=1
+// The rules guarded by has(self.status) validate new HANAMappings, as the API
+// server drops the status on create. Updates are validated by the transition
+// rules, which accept unchanged values, so that objects stored before a rule
+// was added can still be edited and their finalizer removed.
+//+kubebuilder:validation:XValidation:rule="has(self.status) || self.spec.mapping.serviceInstanceID.matches('^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$')",message="serviceInstanceID must be a GUID"
+//+kubebuilder:validation:XValidation:rule="self.spec.mapping.serviceInstanceID == oldSelf.spec.mapping.serviceInstanceID || self.spec.mapping.serviceInstanceID.matches('^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$')",message="serviceInstanceID must be a GUID"
+//+kubebuilder:validation:XValidation:rule="has(self.status) || !has(self.spec.mapping.targetNamespace) || self.spec.mapping.targetNamespace.matches('^[a-z0-9]([-a-z0-9]*[a-z0-9])?$')",message="targetNamespace must be a DNS label"
+//+kubebuilder:validation:XValidation:rule="!has(self.spec.mapping.targetNamespace) || (has(oldSelf.spec.mapping.targetNamespace) && self.spec.mapping.targetNamespace == oldSelf.spec.mapping.targetNamespace) || self.spec.mapping.targetNamespace.matches('^[a-z0-9]([-a-z0-9]*[a-z0-9])?$')",message="targetNamespace must be a DNS label"
+//+kubebuilder:validation:XValidation:rule="has(self.status) || has(self.spec.adminAPIAccessSecret) != has(self.spec.adminCredentials)",message="exactly one of adminAPIAccessSecret and adminCredentials must be set"
+//+kubebuilder:validation:XValidation:rule="(has(self.spec.adminAPIAccessSecret) == has(oldSelf.spec.adminAPIAccessSecret) && has(self.spec.adminCredentials) == has(oldSelf.spec.adminCredentials)) || has(self.spec.adminAPIAccessSecret) != has(self.spec.adminCredentials)",message="exactly one of adminAPIAccessSecret and adminCredentials must be set"
+//+kubebuilder:validation:XValidation:rule="!has(oldSelf.status) || !has(oldSelf.status.mappingID) || self.spec.mapping.serviceInstanceID == oldSelf.spec.mapping.serviceInstanceID || (has(self.spec.allowRemap) && self.spec.allowRemap)",message="serviceInstanceID is immutable once mapped unless allowRemap is set"
 
 // HANAMapping is the Schema for the hanamappings API
 type HANAMapping struct {
@@ -78,14 +117,64 @@ type NamespacedName struct {
 	Name string `json:"name"`
 }
 
+const (
+	PlatformKubernetes   = "kubernetes"
+	PlatformCloudFoundry = "cloudfoundry"
+)
+
+const (
+	DeletionPolicyDelete = "Delete"
+	DeletionPolicyOrphan = "Orphan"
+)
+
+const (
+	// ResyncAnnotation triggers a reconciliation whenever its value changes.
+	ResyncAnnotation = "hana.cloud.sap.com/resync"
+	// AdoptAnnotation set to "true" lets the HANAMapping take over a mapping
+	// which already exists in the inventory. It is removed once the mapping is adopted.
+	AdoptAnnotation = "hana.cloud.sap.com/adopt"
+)
+
+const (
+	// ServiceInstanceIDAnnotation on a namespace makes the operator create a
+	// HANAMapping of the service instance in the namespace.
+	ServiceInstanceIDAnnotation = "hana.cloud.sap.com/service-instance-id"
+	// AdminCredentialsAnnotation on a namespace names the HANAAdminCredentials
+	// in the namespace the created HANAMapping references.
+	AdminCredentialsAnnotation = "hana.cloud.sap.com/admin-credentials"
+	// AdminAPIAccessSecretAnnotation on a namespace references the admin API
+	// access secret of the created HANAMapping as namespace/name.
+	AdminAPIAccessSecretAnnotation = "hana.cloud.sap.com/admin-api-access-secret"
+)
+
+// +kubebuilder:validation:XValidation:rule="(!has(self.primaryID) && !has(self.secondaryID)) || (has(self.platform) && self.platform == 'cloudfoundry')",message="primaryID and secondaryID are only supported on cloudfoundry"
 type Mapping struct {
+	// Platform of the mapping, defaults to kubernetes.
+	// +kubebuilder:validation:Enum=kubernetes;cloudfoundry
+	// +optional
+	Platform string `json:"platform,omitempty"`
+	// +kubebuilder:validation:MaxLength=36
 	// +required
 	ServiceInstanceID string `json:"serviceInstanceID"`
+	// TargetNamespace is the namespace on kubernetes, it defaults to the namespace of the HANAMapping.
+	// +kubebuilder:validation:MaxLength=63
 	// +optional
 	TargetNamespace string `json:"targetNamespace,omitempty"`
+	// PrimaryID is the organization GUID on cloudfoundry. On kubernetes the cluster ID is used instead.
+	// +optional
+	PrimaryID string `json:"primaryID,omitempty"`
+	// SecondaryID is the space GUID on cloudfoundry. On kubernetes the target namespace is used instead.
+	// +optional
+	SecondaryID string `json:"secondaryID,omitempty"`
+	// IsDefault marks the service instance as the default instance of the target namespace.
+	// At most one HANAMapping per target namespace may set it.
+	// +optional
+	IsDefault bool `json:"isDefault,omitempty"`
 }
 
 type MappingID struct {
+	// +optional
+	Platform string `json:"platform,omitempty"`
 	// +required
 	ServiceInstanceID string `json:"serviceInstanceID"`
 	// +required
@@ -94,6 +183,23 @@ type MappingID struct {
 	SecondaryID string `json:"secondaryID"`
 }
 
+const (
+	InventoryOperationCreate = "Create"
+	InventoryOperationDelete = "Delete"
+)
+
+// InventoryOperation is an inventory API call which was started but whose
+// outcome is not recorded in the status yet.
+type InventoryOperation struct {
+	// +kubebuilder:validation:Enum=Create;Delete
+	// +required
+	Type string `json:"type"`
+	// +required
+	MappingID MappingID `json:"mappingID"`
+	// +required
+	StartedAt metav1.Time `json:"startedAt"`
+}
+
 func init() {
 	SchemeBuilder.Register(&HANAMapping{}, &HANAMappingList{})
 }
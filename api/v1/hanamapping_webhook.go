@@ -0,0 +1,126 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var hanamappinglog = logf.Log.WithName("hanamapping-resource")
+
+// SetupWebhookWithManager will setup the manager to manage the webhooks
+func (r *HANAMapping) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithValidator(&hanaMappingValidator{client: mgr.GetClient()}).
+		Complete()
+}
+
+//+kubebuilder:webhook:path=/validate-hana-cloud-sap-com-v1-hanamapping,mutating=false,failurePolicy=fail,sideEffects=None,groups=hana.cloud.sap.com,resources=hanamappings,verbs=create;update,versions=v1,name=vhanamapping.kb.io,admissionReviewVersions=v1
+
+// hanaMappingValidator rejects HANAMappings which reference an admin API
+// access secret they are not permitted to use.
+// +kubebuilder:object:generate=false
+type hanaMappingValidator struct {
+	client client.Reader
+}
+
+var _ webhook.CustomValidator = &hanaMappingValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *hanaMappingValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	hanaMapping, ok := obj.(*HANAMapping)
+	if !ok {
+		return nil, fmt.Errorf("expected a HANAMapping but got a %T", obj)
+	}
+	hanamappinglog.Info("validate create", "name", hanaMapping.Name)
+
+	return nil, v.validateAdminAPIAccessSecret(ctx, hanaMapping)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *hanaMappingValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	oldHANAMapping, ok := oldObj.(*HANAMapping)
+	if !ok {
+		return nil, fmt.Errorf("expected a HANAMapping but got a %T", oldObj)
+	}
+	hanaMapping, ok := newObj.(*HANAMapping)
+	if !ok {
+		return nil, fmt.Errorf("expected a HANAMapping but got a %T", newObj)
+	}
+	hanamappinglog.Info("validate update", "name", hanaMapping.Name)
+
+	// A revoked grant must not block finalizer removal or unrelated changes.
+	if !hanaMapping.DeletionTimestamp.IsZero() || equality.Semantic.DeepEqual(hanaMapping.Spec.AdminAPIAccessSecret, oldHANAMapping.Spec.AdminAPIAccessSecret) {
+		return nil, nil
+	}
+
+	return nil, v.validateAdminAPIAccessSecret(ctx, hanaMapping)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (v *hanaMappingValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (v *hanaMappingValidator) validateAdminAPIAccessSecret(ctx context.Context, hanaMapping *HANAMapping) error {
+	secret := hanaMapping.Spec.AdminAPIAccessSecret
+	if secret == nil {
+		return nil
+	}
+
+	permitted, err := AdminAPIAccessSecretPermitted(ctx, v.client, hanaMapping.Namespace, *secret)
+	if err != nil {
+		return err
+	}
+	if !permitted {
+		return fmt.Errorf("no hanamappingcredentialgrant in namespace %s permits namespace %s to reference secret %s", secret.Namespace, hanaMapping.Namespace, secret.Name)
+	}
+	return nil
+}
+
+// AdminAPIAccessSecretPermitted reports whether HANAMappings in namespace may
+// reference the given admin API access secret. Secrets in the same namespace
+// are always permitted, secrets in other namespaces need a
+// HANAMappingCredentialGrant in the namespace of the secret.
+func AdminAPIAccessSecretPermitted(ctx context.Context, c client.Reader, namespace string, secret NamespacedName) (bool, error) {
+	if secret.Namespace == namespace {
+		return true, nil
+	}
+
+	grants := &HANAMappingCredentialGrantList{}
+	if err := c.List(ctx, grants, client.InNamespace(secret.Namespace)); err != nil {
+		return false, err
+	}
+
+	for i := range grants.Items {
+		if grants.Items[i].Permits(namespace, secret.Name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = Describe("HANAMapping Webhook", func() {
+	var (
+		ctx         context.Context
+		scheme      *runtime.Scheme
+		hanaMapping *HANAMapping
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+
+		hanaMapping = &HANAMapping{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "test-hanamapping"},
+			Spec: HANAMappingSpec{
+				AdminAPIAccessSecret: &NamespacedName{Namespace: "platform", Name: "admin-secret"},
+				Mapping:              Mapping{ServiceInstanceID: "test-serviceinstanceid"},
+			},
+		}
+	})
+
+	newValidator := func(grants ...*HANAMappingCredentialGrant) *hanaMappingValidator {
+		builder := fake.NewClientBuilder().WithScheme(scheme)
+		for _, grant := range grants {
+			builder = builder.WithObjects(grant)
+		}
+		return &hanaMappingValidator{client: builder.Build()}
+	}
+
+	newGrant := func(from string, to ...string) *HANAMappingCredentialGrant {
+		grant := &HANAMappingCredentialGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "platform", Name: "test-grant"},
+			Spec:       HANAMappingCredentialGrantSpec{From: []CredentialGrantFrom{{Namespace: from}}},
+		}
+		for _, name := range to {
+			grant.Spec.To = append(grant.Spec.To, CredentialGrantTo{Name: name})
+		}
+		return grant
+	}
+
+	It("should admit a secret in the same namespace without a grant", func() {
+		hanaMapping.Spec.AdminAPIAccessSecret.Namespace = hanaMapping.Namespace
+
+		_, err := newValidator().ValidateCreate(ctx, hanaMapping)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny a secret in another namespace without a grant", func() {
+		_, err := newValidator().ValidateCreate(ctx, hanaMapping)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should admit a secret in another namespace with a grant", func() {
+		_, err := newValidator(newGrant("team-a")).ValidateCreate(ctx, hanaMapping)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("should deny a secret which is not listed in the grant", func() {
+		_, err := newValidator(newGrant("team-a", "other-secret")).ValidateCreate(ctx, hanaMapping)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should deny a namespace which is not listed in the grant", func() {
+		_, err := newValidator(newGrant("team-b", "admin-secret")).ValidateCreate(ctx, hanaMapping)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should admit an update which keeps the secret after the grant was revoked", func() {
+		oldHANAMapping := hanaMapping.DeepCopy()
+		hanaMapping.Spec.Mapping.IsDefault = true
+
+		_, err := newValidator().ValidateUpdate(ctx, oldHANAMapping, hanaMapping)
+		Expect(err).NotTo(HaveOccurred())
+	})
+})
@@ -0,0 +1,93 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HANAMappingCredentialGrantSpec defines which namespaces may reference the
+// admin API access secrets in the namespace of the grant.
+type HANAMappingCredentialGrantSpec struct {
+	// From lists the namespaces of the HANAMappings which may reference the secrets.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	From []CredentialGrantFrom `json:"from"`
+	// To lists the secrets which may be referenced. All secrets in the namespace of the grant may be referenced if empty.
+	// +optional
+	To []CredentialGrantTo `json:"to,omitempty"`
+}
+
+type CredentialGrantFrom struct {
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Namespace string `json:"namespace"`
+}
+
+type CredentialGrantTo struct {
+	// +kubebuilder:validation:MinLength=1
+	// +required
+	Name string `json:"name"`
+}
+
+//+kubebuilder:object:root=true
+
+// HANAMappingCredentialGrant is the Schema for the hanamappingcredentialgrants API
+type HANAMappingCredentialGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec HANAMappingCredentialGrantSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HANAMappingCredentialGrantList contains a list of HANAMappingCredentialGrant
+type HANAMappingCredentialGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HANAMappingCredentialGrant `json:"items"`
+}
+
+// Permits reports whether HANAMappings in namespace may reference the secret
+// with the given name in the namespace of the grant.
+func (g *HANAMappingCredentialGrant) Permits(namespace, secretName string) bool {
+	fromPermitted := false
+	for _, from := range g.Spec.From {
+		if from.Namespace == namespace {
+			fromPermitted = true
+			break
+		}
+	}
+	if !fromPermitted {
+		return false
+	}
+
+	if len(g.Spec.To) == 0 {
+		return true
+	}
+	for _, to := range g.Spec.To {
+		if to.Name == secretName {
+			return true
+		}
+	}
+	return false
+}
+
+func init() {
+	SchemeBuilder.Register(&HANAMappingCredentialGrant{}, &HANAMappingCredentialGrantList{})
+}
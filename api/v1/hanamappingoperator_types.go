@@ -0,0 +1,86 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Module states as expected by the Kyma Lifecycle Manager.
+const (
+	StateReady      = "Ready"
+	StateProcessing = "Processing"
+	StateError      = "Error"
+	StateDeleting   = "Deleting"
+)
+
+// HANAMappingOperatorSpec defines the desired state of HANAMappingOperator
+type HANAMappingOperatorSpec struct {
+	// BTPOperatorConfigmap is the config map of the BTP operator which must be
+	// present, defaults to the one configured for the operator.
+	// +optional
+	BTPOperatorConfigmap *NamespacedName `json:"btpOperatorConfigmap,omitempty"`
+}
+
+// HANAMappingOperatorStatus defines the observed state of HANAMappingOperator
+type HANAMappingOperatorStatus struct {
+	// State is the state of the module in the vocabulary of the Kyma Lifecycle Manager.
+	// +kubebuilder:validation:Enum=Ready;Processing;Error;Deleting
+	// +optional
+	State string `json:"state,omitempty"`
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// TotalMappings is the number of HANAMappings managed by the operator.
+	// +optional
+	TotalMappings int32 `json:"totalMappings,omitempty"`
+	// ReadyMappings is the number of ready HANAMappings.
+	// +optional
+	ReadyMappings int32 `json:"readyMappings,omitempty"`
+	// FailedMappings is the number of HANAMappings which failed to reconcile.
+	// +optional
+	FailedMappings int32 `json:"failedMappings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="State",type="string",JSONPath=`.status.state`,description="State"
+//+kubebuilder:printcolumn:name="Mappings",type="integer",JSONPath=`.status.totalMappings`,description="Mappings"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=`.status.readyMappings`,description="Ready"
+//+kubebuilder:printcolumn:name="Age",type="date",JSONPath=`.metadata.creationTimestamp`
+
+// HANAMappingOperator is the Kyma module resource of the operator. It reports
+// the state of the prerequisites and of all HANAMappings.
+type HANAMappingOperator struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HANAMappingOperatorSpec   `json:"spec,omitempty"`
+	Status HANAMappingOperatorStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HANAMappingOperatorList contains a list of HANAMappingOperator
+type HANAMappingOperatorList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HANAMappingOperator `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HANAMappingOperator{}, &HANAMappingOperatorList{})
+}
@@ -0,0 +1,159 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// MappingSetLabel is set on generated HANAMappings to the name of their HANAMappingSet.
+const MappingSetLabel = "hana.cloud.sap.com/mapping-set"
+
+// ServiceInstanceLabel is set on generated HANAMappings to the name of the
+// ServiceInstance their service instance ID was read from.
+const ServiceInstanceLabel = "hana.cloud.sap.com/service-instance"
+
+// HANAMappingSetSpec defines the desired state of HANAMappingSet
+// +kubebuilder:validation:XValidation:rule="self.generators.exists(g, has(g.namespaceSelector) || has(g.namespaces))",message="a generator of target namespaces is required"
+// +kubebuilder:validation:XValidation:rule="self.generators.exists(g, has(g.serviceInstances))",message="a generator of service instances is required"
+type HANAMappingSetSpec struct {
+	// Generators produce the target namespaces and the service instances. A
+	// HANAMapping is generated for every combination of both.
+	// +kubebuilder:validation:MinItems=1
+	// +required
+	Generators []HANAMappingSetGenerator `json:"generators"`
+	// +required
+	Template HANAMappingTemplate `json:"template"`
+	// +optional
+	Strategy HANAMappingSetStrategy `json:"strategy,omitempty"`
+}
+
+// HANAMappingSetGenerator sets exactly one of its generators.
+// +kubebuilder:validation:XValidation:rule="[has(self.namespaceSelector), has(self.namespaces), has(self.serviceInstances)].filter(x, x).size() == 1",message="exactly one of namespaceSelector, namespaces and serviceInstances must be set"
+type HANAMappingSetGenerator struct {
+	// NamespaceSelector selects the target namespaces by their labels.
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Namespaces lists target namespaces.
+	// +listType=set
+	// +optional
+	Namespaces []string `json:"namespaces,omitempty"`
+	// ServiceInstances lists the service instances to map.
+	// +optional
+	ServiceInstances []ServiceInstanceReference `json:"serviceInstances,omitempty"`
+}
+
+// ServiceInstanceReference identifies a service instance either by its ID or
+// by a ServiceInstance of the BTP operator in the namespace of the set.
+// +kubebuilder:validation:XValidation:rule="has(self.id) != has(self.name)",message="exactly one of id and name must be set"
+type ServiceInstanceReference struct {
+	// +kubebuilder:validation:MaxLength=36
+	// +kubebuilder:validation:XValidation:rule="self.matches('^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$')",message="id must be a GUID"
+	// +optional
+	ID string `json:"id,omitempty"`
+	// Name of a ServiceInstance whose instance ID is mapped.
+	// +optional
+	Name string `json:"name,omitempty"`
+}
+
+// HANAMappingTemplate describes the generated HANAMappings.
+type HANAMappingTemplate struct {
+	// +optional
+	Metadata HANAMappingTemplateMetadata `json:"metadata,omitempty"`
+	// +required
+	Spec HANAMappingTemplateSpec `json:"spec"`
+}
+
+type HANAMappingTemplateMetadata struct {
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+	// +optional
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// HANAMappingTemplateSpec is the spec of the generated HANAMappings without the
+// service instance and the target namespace, which are generated.
+// +kubebuilder:validation:XValidation:rule="has(self.adminAPIAccessSecret) != has(self.adminCredentials)",message="exactly one of adminAPIAccessSecret and adminCredentials must be set"
+type HANAMappingTemplateSpec struct {
+	// +optional
+	BTPOperatorConfigmap NamespacedName `json:"btpOperatorConfigmap,omitempty"`
+	// +optional
+	AdminAPIAccessSecret *NamespacedName `json:"adminAPIAccessSecret,omitempty"`
+	// AdminCredentials is the name of a HANAAdminCredentials in the namespace of the set.
+	// +optional
+	AdminCredentials string `json:"adminCredentials,omitempty"`
+	// +optional
+	IsDefault bool `json:"isDefault,omitempty"`
+	// +kubebuilder:validation:Enum=Delete;Orphan
+	// +optional
+	DeletionPolicy string `json:"deletionPolicy,omitempty"`
+	// +kubebuilder:validation:MaxLength=253
+	// +optional
+	ClusterID string `json:"clusterID,omitempty"`
+}
+
+type HANAMappingSetStrategy struct {
+	// MaxUnavailable is the number or percentage of generated HANAMappings which
+	// may be updated or deleted while they or others are not ready. Defaults to 1.
+	// +kubebuilder:validation:XIntOrString
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+}
+
+// HANAMappingSetStatus defines the observed state of HANAMappingSet
+type HANAMappingSetStatus struct {
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// DesiredMappings is the number of generated HANAMappings.
+	// +optional
+	DesiredMappings int32 `json:"desiredMappings,omitempty"`
+	// UpdatedMappings is the number of HANAMappings which match the template.
+	// +optional
+	UpdatedMappings int32 `json:"updatedMappings,omitempty"`
+	// ReadyMappings is the number of HANAMappings which match the template and are ready.
+	// +optional
+	ReadyMappings int32 `json:"readyMappings,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:subresource:status
+//+kubebuilder:printcolumn:name="Desired",type="integer",JSONPath=`.status.desiredMappings`,description="Desired"
+//+kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=`.status.readyMappings`,description="Ready"
+//+kubebuilder:printcolumn:name="Status",type="string",JSONPath=`.status.conditions[?(@.type=='Ready')].status`,description="Status"
+
+// HANAMappingSet is the Schema for the hanamappingsets API
+type HANAMappingSet struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   HANAMappingSetSpec   `json:"spec,omitempty"`
+	Status HANAMappingSetStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// HANAMappingSetList contains a list of HANAMappingSet
+type HANAMappingSetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []HANAMappingSet `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&HANAMappingSet{}, &HANAMappingSetList{})
+}
@@ -23,29 +23,537 @@ package v1
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHANAMapping) DeepCopyInto(out *ClusterHANAMapping) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHANAMapping.
+func (in *ClusterHANAMapping) DeepCopy() *ClusterHANAMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHANAMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterHANAMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHANAMappingList) DeepCopyInto(out *ClusterHANAMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterHANAMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHANAMappingList.
+func (in *ClusterHANAMappingList) DeepCopy() *ClusterHANAMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHANAMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterHANAMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHANAMappingSpec) DeepCopyInto(out *ClusterHANAMappingSpec) {
+	*out = *in
+	out.BTPOperatorConfigmap = in.BTPOperatorConfigmap
+	if in.TargetNamespaces != nil {
+		in, out := &in.TargetNamespaces, &out.TargetNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHANAMappingSpec.
+func (in *ClusterHANAMappingSpec) DeepCopy() *ClusterHANAMappingSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHANAMappingSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterHANAMappingStatus) DeepCopyInto(out *ClusterHANAMappingStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MappingIDs != nil {
+		in, out := &in.MappingIDs, &out.MappingIDs
+		*out = make([]MappingID, len(*in))
+		copy(*out, *in)
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]InventoryOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterHANAMappingStatus.
+func (in *ClusterHANAMappingStatus) DeepCopy() *ClusterHANAMappingStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterHANAMappingStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialGrantFrom) DeepCopyInto(out *CredentialGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialGrantFrom.
+func (in *CredentialGrantFrom) DeepCopy() *CredentialGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CredentialGrantTo) DeepCopyInto(out *CredentialGrantTo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CredentialGrantTo.
+func (in *CredentialGrantTo) DeepCopy() *CredentialGrantTo {
+	if in == nil {
+		return nil
+	}
+	out := new(CredentialGrantTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAAdminCredentials) DeepCopyInto(out *HANAAdminCredentials) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAAdminCredentials.
+func (in *HANAAdminCredentials) DeepCopy() *HANAAdminCredentials {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAAdminCredentials)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAAdminCredentials) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAAdminCredentialsList) DeepCopyInto(out *HANAAdminCredentialsList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HANAAdminCredentials, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAAdminCredentialsList.
+func (in *HANAAdminCredentialsList) DeepCopy() *HANAAdminCredentialsList {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAAdminCredentialsList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAAdminCredentialsList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAAdminCredentialsSpec) DeepCopyInto(out *HANAAdminCredentialsSpec) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.ServiceBindingRef != nil {
+		in, out := &in.ServiceBindingRef, &out.ServiceBindingRef
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAAdminCredentialsSpec.
+func (in *HANAAdminCredentialsSpec) DeepCopy() *HANAAdminCredentialsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAAdminCredentialsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAAdminCredentialsStatus) DeepCopyInto(out *HANAAdminCredentialsStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAAdminCredentialsStatus.
+func (in *HANAAdminCredentialsStatus) DeepCopy() *HANAAdminCredentialsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAAdminCredentialsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAInstanceInventory) DeepCopyInto(out *HANAInstanceInventory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAInstanceInventory.
+func (in *HANAInstanceInventory) DeepCopy() *HANAInstanceInventory {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAInstanceInventory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAInstanceInventory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAInstanceInventoryList) DeepCopyInto(out *HANAInstanceInventoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HANAInstanceInventory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAInstanceInventoryList.
+func (in *HANAInstanceInventoryList) DeepCopy() *HANAInstanceInventoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAInstanceInventoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAInstanceInventoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAInstanceInventorySpec) DeepCopyInto(out *HANAInstanceInventorySpec) {
+	*out = *in
+	if in.AdminAPIAccessSecret != nil {
+		in, out := &in.AdminAPIAccessSecret, &out.AdminAPIAccessSecret
+		*out = new(NamespacedName)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAInstanceInventorySpec.
+func (in *HANAInstanceInventorySpec) DeepCopy() *HANAInstanceInventorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAInstanceInventorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAInstanceInventoryStatus) DeepCopyInto(out *HANAInstanceInventoryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.LastSyncTime != nil {
+		in, out := &in.LastSyncTime, &out.LastSyncTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Mappings != nil {
+		in, out := &in.Mappings, &out.Mappings
+		*out = make([]InventoryMapping, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAInstanceInventoryStatus.
+func (in *HANAInstanceInventoryStatus) DeepCopy() *HANAInstanceInventoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAInstanceInventoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HANAMapping) DeepCopyInto(out *HANAMapping) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMapping.
+func (in *HANAMapping) DeepCopy() *HANAMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAMapping) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingCredentialGrant) DeepCopyInto(out *HANAMappingCredentialGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingCredentialGrant.
+func (in *HANAMappingCredentialGrant) DeepCopy() *HANAMappingCredentialGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingCredentialGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAMappingCredentialGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingCredentialGrantList) DeepCopyInto(out *HANAMappingCredentialGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HANAMappingCredentialGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingCredentialGrantList.
+func (in *HANAMappingCredentialGrantList) DeepCopy() *HANAMappingCredentialGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingCredentialGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAMappingCredentialGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingCredentialGrantSpec) DeepCopyInto(out *HANAMappingCredentialGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]CredentialGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]CredentialGrantTo, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingCredentialGrantSpec.
+func (in *HANAMappingCredentialGrantSpec) DeepCopy() *HANAMappingCredentialGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingCredentialGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingList) DeepCopyInto(out *HANAMappingList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HANAMapping, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingList.
+func (in *HANAMappingList) DeepCopy() *HANAMappingList {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAMappingList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingOperator) DeepCopyInto(out *HANAMappingOperator) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMapping.
-func (in *HANAMapping) DeepCopy() *HANAMapping {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingOperator.
+func (in *HANAMappingOperator) DeepCopy() *HANAMappingOperator {
 	if in == nil {
 		return nil
 	}
-	out := new(HANAMapping)
+	out := new(HANAMappingOperator)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HANAMapping) DeepCopyObject() runtime.Object {
+func (in *HANAMappingOperator) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -53,42 +561,243 @@ func (in *HANAMapping) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HANAMappingList) DeepCopyInto(out *HANAMappingList) {
+func (in *HANAMappingOperatorList) DeepCopyInto(out *HANAMappingOperatorList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HANAMapping, len(*in))
+		*out = make([]HANAMappingOperator, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingList.
-func (in *HANAMappingList) DeepCopy() *HANAMappingList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingOperatorList.
+func (in *HANAMappingOperatorList) DeepCopy() *HANAMappingOperatorList {
 	if in == nil {
 		return nil
 	}
-	out := new(HANAMappingList)
+	out := new(HANAMappingOperatorList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HANAMappingList) DeepCopyObject() runtime.Object {
+func (in *HANAMappingOperatorList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingOperatorSpec) DeepCopyInto(out *HANAMappingOperatorSpec) {
+	*out = *in
+	if in.BTPOperatorConfigmap != nil {
+		in, out := &in.BTPOperatorConfigmap, &out.BTPOperatorConfigmap
+		*out = new(NamespacedName)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingOperatorSpec.
+func (in *HANAMappingOperatorSpec) DeepCopy() *HANAMappingOperatorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingOperatorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingOperatorStatus) DeepCopyInto(out *HANAMappingOperatorStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingOperatorStatus.
+func (in *HANAMappingOperatorStatus) DeepCopy() *HANAMappingOperatorStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingOperatorStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingSet) DeepCopyInto(out *HANAMappingSet) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingSet.
+func (in *HANAMappingSet) DeepCopy() *HANAMappingSet {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAMappingSet) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingSetGenerator) DeepCopyInto(out *HANAMappingSetGenerator) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ServiceInstances != nil {
+		in, out := &in.ServiceInstances, &out.ServiceInstances
+		*out = make([]ServiceInstanceReference, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingSetGenerator.
+func (in *HANAMappingSetGenerator) DeepCopy() *HANAMappingSetGenerator {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingSetGenerator)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingSetList) DeepCopyInto(out *HANAMappingSetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HANAMappingSet, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingSetList.
+func (in *HANAMappingSetList) DeepCopy() *HANAMappingSetList {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingSetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HANAMappingSetList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingSetSpec) DeepCopyInto(out *HANAMappingSetSpec) {
+	*out = *in
+	if in.Generators != nil {
+		in, out := &in.Generators, &out.Generators
+		*out = make([]HANAMappingSetGenerator, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	in.Template.DeepCopyInto(&out.Template)
+	in.Strategy.DeepCopyInto(&out.Strategy)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingSetSpec.
+func (in *HANAMappingSetSpec) DeepCopy() *HANAMappingSetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingSetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingSetStatus) DeepCopyInto(out *HANAMappingSetStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingSetStatus.
+func (in *HANAMappingSetStatus) DeepCopy() *HANAMappingSetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingSetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingSetStrategy) DeepCopyInto(out *HANAMappingSetStrategy) {
+	*out = *in
+	if in.MaxUnavailable != nil {
+		in, out := &in.MaxUnavailable, &out.MaxUnavailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingSetStrategy.
+func (in *HANAMappingSetStrategy) DeepCopy() *HANAMappingSetStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingSetStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HANAMappingSpec) DeepCopyInto(out *HANAMappingSpec) {
 	*out = *in
 	out.BTPOperatorConfigmap = in.BTPOperatorConfigmap
-	out.AdminAPIAccessSecret = in.AdminAPIAccessSecret
+	if in.AdminAPIAccessSecret != nil {
+		in, out := &in.AdminAPIAccessSecret, &out.AdminAPIAccessSecret
+		*out = new(NamespacedName)
+		**out = **in
+	}
 	out.Mapping = in.Mapping
 }
 
@@ -117,6 +826,18 @@ func (in *HANAMappingStatus) DeepCopyInto(out *HANAMappingStatus) {
 		*out = new(MappingID)
 		**out = **in
 	}
+	if in.PendingMappingID != nil {
+		in, out := &in.PendingMappingID, &out.PendingMappingID
+		*out = new(MappingID)
+		**out = **in
+	}
+	if in.Operations != nil {
+		in, out := &in.Operations, &out.Operations
+		*out = make([]InventoryOperation, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingStatus.
@@ -129,6 +850,120 @@ func (in *HANAMappingStatus) DeepCopy() *HANAMappingStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingTemplate) DeepCopyInto(out *HANAMappingTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingTemplate.
+func (in *HANAMappingTemplate) DeepCopy() *HANAMappingTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingTemplateMetadata) DeepCopyInto(out *HANAMappingTemplateMetadata) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Annotations != nil {
+		in, out := &in.Annotations, &out.Annotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingTemplateMetadata.
+func (in *HANAMappingTemplateMetadata) DeepCopy() *HANAMappingTemplateMetadata {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingTemplateMetadata)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HANAMappingTemplateSpec) DeepCopyInto(out *HANAMappingTemplateSpec) {
+	*out = *in
+	out.BTPOperatorConfigmap = in.BTPOperatorConfigmap
+	if in.AdminAPIAccessSecret != nil {
+		in, out := &in.AdminAPIAccessSecret, &out.AdminAPIAccessSecret
+		*out = new(NamespacedName)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HANAMappingTemplateSpec.
+func (in *HANAMappingTemplateSpec) DeepCopy() *HANAMappingTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HANAMappingTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryMapping) DeepCopyInto(out *InventoryMapping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryMapping.
+func (in *InventoryMapping) DeepCopy() *InventoryMapping {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryMapping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InventoryOperation) DeepCopyInto(out *InventoryOperation) {
+	*out = *in
+	out.MappingID = in.MappingID
+	in.StartedAt.DeepCopyInto(&out.StartedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InventoryOperation.
+func (in *InventoryOperation) DeepCopy() *InventoryOperation {
+	if in == nil {
+		return nil
+	}
+	out := new(InventoryOperation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LocalObjectReference.
+func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
+	if in == nil {
+		return nil
+	}
+	out := new(LocalObjectReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Mapping) DeepCopyInto(out *Mapping) {
 	*out = *in
@@ -173,3 +1008,18 @@ func (in *NamespacedName) DeepCopy() *NamespacedName {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceInstanceReference) DeepCopyInto(out *ServiceInstanceReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceInstanceReference.
+func (in *ServiceInstanceReference) DeepCopy() *ServiceInstanceReference {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceInstanceReference)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"strconv"
+	"strings"
+
+	configv1alpha1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/config/v1alpha1"
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+// bindFlags binds the flags which override the configuration file to c.
+func bindFlags(fs *flag.FlagSet, c *configv1alpha1.OperatorConfiguration) {
+	fs.StringVar(&c.HTTP.MetricsBindAddress, "metrics-bind-address", c.HTTP.MetricsBindAddress, "The address the metric endpoint binds to.")
+	fs.StringVar(&c.HTTP.HealthProbeBindAddress, "health-probe-bind-address", c.HTTP.HealthProbeBindAddress, "The address the probe endpoint binds to.")
+	fs.BoolVar(&c.Controller.LeaderElect, "leader-elect", c.Controller.LeaderElect,
+		"Enable leader election for controller manager. "+
+			"Enabling this will ensure there is only one active controller manager.")
+	fs.BoolVar(&c.HTTP.SecureMetrics, "metrics-secure", c.HTTP.SecureMetrics,
+		"If set the metrics endpoint is served securely")
+	fs.BoolVar(&c.HTTP.EnableHTTP2, "enable-http2", c.HTTP.EnableHTTP2,
+		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	fs.IntVar(&c.Controller.MaxConcurrentReconciles, "max-concurrent-reconciles", c.Controller.MaxConcurrentReconciles,
+		"The maximum number of HANAMappings which are reconciled concurrently.")
+	fs.Float64Var(&c.Inventory.QPS, "inventory-qps", c.Inventory.QPS,
+		"The maximum number of requests per second sent to the inventory API per admin API access binding. "+
+			"A value <= 0 disables rate limiting.")
+	fs.IntVar(&c.Inventory.Burst, "inventory-burst", c.Inventory.Burst,
+		"The maximum burst of requests sent to the inventory API per admin API access binding.")
+	fs.IntVar(&c.Inventory.FailureThreshold, "inventory-failure-threshold", c.Inventory.FailureThreshold,
+		"The number of consecutive inventory API failures per admin API access binding after which the circuit breaker opens. "+
+			"A value <= 0 disables the circuit breaker.")
+	fs.DurationVar(&c.Inventory.CircuitCooldown.Duration, "inventory-circuit-cooldown", c.Inventory.CircuitCooldown.Duration,
+		"The time an open circuit breaker waits before letting a probe request through to the inventory API.")
+	fs.StringVar(&c.Controller.OperatorNamespace, "operator-namespace", c.Controller.OperatorNamespace,
+		"The namespace of the operator. ClusterHANAMappings only read admin API access secrets from this namespace.")
+	fs.Var(&stringListFlag{target: &c.Controller.WatchNamespaces}, "watch-namespaces",
+		"A comma-separated list of namespaces the operator is restricted to. All namespaces are watched if it is empty.")
+	fs.StringVar(&c.Defaults.ClusterID, "cluster-id", c.Defaults.ClusterID,
+		"The cluster ID used as primary ID of kubernetes mappings. It overrides --cluster-id-source, "+
+			"HANAMappings can override it with spec.clusterID.")
+	fs.StringVar(&c.Defaults.ClusterIDSource, "cluster-id-source", c.Defaults.ClusterIDSource,
+		"Where the cluster ID is read from, either configmap for the BTP operator config map "+
+			"or kube-system for the UID of the kube-system namespace.")
+	fs.StringVar(&c.Defaults.ClusterIDConfigMapKey, "cluster-id-configmap-key", c.Defaults.ClusterIDConfigMapKey,
+		"The key of the cluster ID in the BTP operator config map.")
+	fs.DurationVar(&c.Resync.ClusterIDMigrationInterval.Duration, "cluster-id-migration-interval", c.Resync.ClusterIDMigrationInterval.Duration,
+		"How often the cluster ID of the HANAMappings is checked. Mappings of a changed cluster ID are migrated.")
+	fs.IntVar(&c.Resync.ClusterIDMigrationParallelism, "cluster-id-migration-parallelism", c.Resync.ClusterIDMigrationParallelism,
+		"The maximum number of HANAMappings which are migrated to a new cluster ID concurrently.")
+	fs.DurationVar(&c.Resync.CredentialsRevalidationInterval.Duration, "credentials-revalidation-interval", c.Resync.CredentialsRevalidationInterval.Duration,
+		"How often ready HANAAdminCredentials fetch a token again to notice revoked credentials. 0 disables the revalidation.")
+	fs.DurationVar(&c.Resync.MappingResyncInterval.Duration, "mapping-resync-interval", c.Resync.MappingResyncInterval.Duration,
+		"How often synced HANAMappings are compared with the inventory again to correct drift. 0 disables the resync.")
+	fs.DurationVar(&c.Resync.ObjectCacheTTL.Duration, "object-cache-ttl", c.Resync.ObjectCacheTTL.Duration,
+		"How long secrets and config maps read from the API server are cached.")
+	fs.Var(&featureGateFlag{config: c, feature: configv1alpha1.FeatureNamespaceController}, "enable-namespace-controller",
+		"If set, a HANAMapping is created in every namespace annotated with hana.cloud.sap.com/service-instance-id.")
+	fs.Var(&namespacedNameFlag{target: &c.Defaults.NamespaceAdminAPIAccessSecret}, "namespace-admin-api-access-secret",
+		"The admin API access secret as namespace/name referenced by the HANAMappings of annotated namespaces "+
+			"which do not annotate credentials themselves.")
+}
+
+// featureGateFlag sets a feature gate of the configuration.
+type featureGateFlag struct {
+	config  *configv1alpha1.OperatorConfiguration
+	feature string
+}
+
+func (f *featureGateFlag) String() string {
+	if f.config == nil {
+		return "false"
+	}
+	return strconv.FormatBool(f.config.FeatureEnabled(f.feature))
+}
+
+func (f *featureGateFlag) Set(value string) error {
+	enabled, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	if f.config.FeatureGates == nil {
+		f.config.FeatureGates = map[string]bool{}
+	}
+	f.config.FeatureGates[f.feature] = enabled
+	return nil
+}
+
+func (f *featureGateFlag) IsBoolFlag() bool {
+	return true
+}
+
+// namespacedNameFlag parses namespace/name.
+type namespacedNameFlag struct {
+	target **hanav1.NamespacedName
+}
+
+func (f *namespacedNameFlag) String() string {
+	if f.target == nil || *f.target == nil {
+		return ""
+	}
+	return (*f.target).Namespace + "/" + (*f.target).Name
+}
+
+func (f *namespacedNameFlag) Set(value string) error {
+	if len(value) == 0 {
+		*f.target = nil
+		return nil
+	}
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || len(namespace) == 0 || len(name) == 0 {
+		return errors.New("must be given as namespace/name")
+	}
+	*f.target = &hanav1.NamespacedName{Namespace: namespace, Name: name}
+	return nil
+}
+
+// stringListFlag parses a comma-separated list.
+type stringListFlag struct {
+	target *[]string
+}
+
+func (f *stringListFlag) String() string {
+	if f.target == nil {
+		return ""
+	}
+	return strings.Join(*f.target, ",")
+}
+
+func (f *stringListFlag) Set(value string) error {
+	*f.target = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); len(item) > 0 {
+			*f.target = append(*f.target, item)
+		}
+	}
+	return nil
+}
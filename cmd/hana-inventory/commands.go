@@ -0,0 +1,200 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+func configureList(fs *flag.FlagSet, o *options) {
+	fs.Var(&o.instances, "instance", "Service instance ID.")
+	fs.StringVar(&o.output, "o", "table", "Output format, one of table, json or yaml.")
+}
+
+func runList(ctx context.Context, o *options) error {
+	if len(o.instances) != 1 {
+		return fmt.Errorf("--instance must be set once")
+	}
+
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	mappings, err := c.ListMappings(ctx, o.instances[0])
+	if err != nil {
+		return err
+	}
+
+	if o.output == "table" {
+		return writeMappingsTable(os.Stdout, mappings)
+	}
+	return writeOutput(os.Stdout, o.output, mappings)
+}
+
+func configureMappingFlags(fs *flag.FlagSet, o *options) {
+	fs.Var(&o.instances, "instance", "Service instance ID.")
+	fs.StringVar(&o.primaryID, "primary-id", "", "Primary ID of the mapping, the cluster ID on kubernetes.")
+	fs.StringVar(&o.secondaryID, "secondary-id", "", "Secondary ID of the mapping, the namespace on kubernetes.")
+	fs.StringVar(&o.platform, "platform", hanav1.PlatformKubernetes, "Platform of the mapping, kubernetes or cloudfoundry.")
+}
+
+func (o *options) validateMappingFlags() error {
+	if len(o.instances) != 1 {
+		return fmt.Errorf("--instance must be set once")
+	}
+	if len(o.primaryID) == 0 || len(o.secondaryID) == 0 {
+		return fmt.Errorf("--primary-id and --secondary-id must be set")
+	}
+	switch o.platform {
+	case hanav1.PlatformKubernetes, hanav1.PlatformCloudFoundry:
+		return nil
+	default:
+		return fmt.Errorf("unsupported platform %q, must be %s or %s", o.platform, hanav1.PlatformKubernetes, hanav1.PlatformCloudFoundry)
+	}
+}
+
+func configureCreate(fs *flag.FlagSet, o *options) {
+	configureMappingFlags(fs, o)
+	fs.BoolVar(&o.isDefault, "default", false, "Make the instance the default instance of the target.")
+	fs.BoolVar(&o.owned, "owned", false, "Accept an existing mapping and recreate it if its default flag differs, like the operator does for mappings it owns.")
+}
+
+// runCreate creates a mapping. Like the operator it fails if the mapping exists
+// already, unless it is marked as owned.
+func runCreate(ctx context.Context, o *options) error {
+	if err := o.validateMappingFlags(); err != nil {
+		return err
+	}
+
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	mapping := inventory.Mapping{
+		Platform:    o.platform,
+		PrimaryID:   o.primaryID,
+		SecondaryID: o.secondaryID,
+		IsDefault:   o.isDefault,
+	}
+	if err := inventory.EnsureMapping(ctx, c, o.instances[0], mapping, o.owned); err != nil {
+		return err
+	}
+	fmt.Printf("mapping %s/%s of service instance %s created\n", o.primaryID, o.secondaryID, o.instances[0])
+	return nil
+}
+
+func configureDelete(fs *flag.FlagSet, o *options) {
+	configureMappingFlags(fs, o)
+}
+
+// runDelete deletes a mapping and succeeds if it does not exist.
+func runDelete(ctx context.Context, o *options) error {
+	if err := o.validateMappingFlags(); err != nil {
+		return err
+	}
+
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	if err := inventory.RemoveMapping(ctx, c, o.instances[0], o.platform, o.primaryID, o.secondaryID); err != nil {
+		return err
+	}
+	fmt.Printf("mapping %s/%s of service instance %s deleted\n", o.primaryID, o.secondaryID, o.instances[0])
+	return nil
+}
+
+func configureExport(fs *flag.FlagSet, o *options) {
+	fs.Var(&o.instances, "instance", "Service instance ID, may be given multiple times.")
+	fs.StringVar(&o.output, "o", "yaml", "Output format, one of json or yaml.")
+}
+
+// runExport prints the mappings of the instances in the format import reads.
+func runExport(ctx context.Context, o *options) error {
+	if len(o.instances) == 0 {
+		return fmt.Errorf("--instance must be set")
+	}
+
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	exported := make([]instanceMappings, 0, len(o.instances))
+	for _, serviceInstanceID := range o.instances {
+		mappings, err := c.ListMappings(ctx, serviceInstanceID)
+		if err != nil {
+			return fmt.Errorf("failed to list the mappings of service instance %s: %w", serviceInstanceID, err)
+		}
+		exported = append(exported, instanceMappings{ServiceInstanceID: serviceInstanceID, Mappings: mappings})
+	}
+
+	return writeOutput(os.Stdout, o.output, exported)
+}
+
+func configureImport(fs *flag.FlagSet, o *options) {
+	fs.StringVar(&o.file, "f", "", "File with the mappings to import, as written by export.")
+	fs.BoolVar(&o.owned, "owned", false, "Accept existing mappings and recreate them if their default flag differs, like the operator does for mappings it owns.")
+}
+
+// runImport creates the mappings of a file written by export. All mappings are
+// attempted, the command fails if any of them failed.
+func runImport(ctx context.Context, o *options) error {
+	if len(o.file) == 0 {
+		return fmt.Errorf("-f must be set")
+	}
+
+	content, err := os.ReadFile(o.file)
+	if err != nil {
+		return err
+	}
+	imported, err := readInstanceMappings(content)
+	if err != nil {
+		return err
+	}
+
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, instance := range imported {
+		for _, mapping := range instance.Mappings {
+			if err := inventory.EnsureMapping(ctx, c, instance.ServiceInstanceID, mapping, o.owned); err != nil {
+				failed++
+				fmt.Fprintf(os.Stderr, "mapping %s/%s of service instance %s failed: %s\n", mapping.PrimaryID, mapping.SecondaryID, instance.ServiceInstanceID, err)
+				continue
+			}
+			fmt.Printf("mapping %s/%s of service instance %s created\n", mapping.PrimaryID, mapping.SecondaryID, instance.ServiceInstanceID)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d mapping(s) failed to import", failed)
+	}
+	return nil
+}
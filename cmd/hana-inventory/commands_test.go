@@ -0,0 +1,109 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+var _ = Describe("export and import", func() {
+	exported := []instanceMappings{
+		{
+			ServiceInstanceID: "2e1a6b4d-7c3f-4f8e-9a5b-1d2c3e4f5a6b",
+			Mappings: []inventory.Mapping{
+				{Platform: hanav1.PlatformKubernetes, PrimaryID: "cluster-1", SecondaryID: "team-a", IsDefault: true},
+				{Platform: hanav1.PlatformCloudFoundry, PrimaryID: "org-guid", SecondaryID: "space-guid"},
+			},
+		},
+		{ServiceInstanceID: "9f8e7d6c-5b4a-4c3d-8e2f-1a0b9c8d7e6f", Mappings: []inventory.Mapping{}},
+	}
+
+	DescribeTable("should import the mappings it exported",
+		func(format string) {
+			out := &bytes.Buffer{}
+			Expect(writeOutput(out, format, exported)).To(Succeed())
+
+			imported, err := readInstanceMappings(out.Bytes())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(imported).To(Equal(exported))
+		},
+		Entry("in YAML", "yaml"),
+		Entry("in JSON", "json"),
+	)
+})
+
+var _ = Describe("secretData", func() {
+	It("should read data and stringData, preferring stringData", func() {
+		data, err := secretData([]byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: admin-api-access
+data:
+  baseurl: aHR0cHM6Ly9vbGQuZXhhbXBsZS5jb20=
+  uaa: eyJjbGllbnRpZCI6ImNsaWVudCJ9
+stringData:
+  baseurl: https://api.example.com
+`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).To(Equal(map[string][]byte{
+			"baseurl": []byte("https://api.example.com"),
+			"uaa":     []byte(`{"clientid":"client"}`),
+		}))
+	})
+
+	It("should read the binding of --secret-file from stringData", func() {
+		secretFile := filepath.Join(GinkgoT().TempDir(), "secret.yaml")
+		Expect(os.WriteFile(secretFile, []byte(`apiVersion: v1
+kind: Secret
+metadata:
+  name: admin-api-access
+stringData:
+  baseurl: https://api.example.com
+  uaa: '{"url":"https://uaa.example.com","clientid":"client","clientsecret":"secret"}'
+`), 0o600)).To(Succeed())
+
+		o := &options{secretFile: secretFile}
+		c, err := o.client()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c).NotTo(BeNil())
+	})
+})
+
+var _ = Describe("validateMappingFlags", func() {
+	DescribeTable("should validate the platform",
+		func(platform string, valid bool) {
+			o := &options{instances: stringList{"instance"}, primaryID: "primary", secondaryID: "secondary", platform: platform}
+			if valid {
+				Expect(o.validateMappingFlags()).To(Succeed())
+			} else {
+				Expect(o.validateMappingFlags()).To(MatchError(ContainSubstring("unsupported platform")))
+			}
+		},
+		Entry("kubernetes", hanav1.PlatformKubernetes, true),
+		Entry("cloudfoundry", hanav1.PlatformCloudFoundry, true),
+		Entry("empty", "", false),
+		Entry("unknown", "kyma", false),
+	)
+})
@@ -0,0 +1,215 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+func configureGenerate(fs *flag.FlagSet, o *options) {
+	fs.Var(&o.instances, "instance", "Service instance ID, may be given multiple times.")
+	fs.StringVar(&o.clusterID, "cluster-id", os.Getenv("CLUSTER_ID"), "Cluster ID whose mappings are generated, defaults to $CLUSTER_ID.")
+	fs.StringVar(&o.adminCredentials, "admin-credentials", "", "Name of the HANAAdminCredentials the HANAMappings reference in their namespace.")
+	fs.StringVar(&o.adminSecret, "admin-secret", "", "Admin API access secret the HANAMappings reference, as namespace/name.")
+	fs.StringVar(&o.btpOperatorConfigmap, "btp-operator-configmap", "kyma-system/sap-btp-operator-config", "BTP operator configmap the HANAMappings reference, as namespace/name.")
+	fs.StringVar(&o.namePrefix, "name-prefix", "hana", "Prefix of the HANAMapping names, which end with the service instance ID.")
+}
+
+// runGenerate prints a HANAMapping manifest for every kubernetes mapping of the
+// instances in the cluster. The HANAMappings are annotated to adopt the
+// existing mappings, so that applying them does not fail.
+func runGenerate(ctx context.Context, o *options) error {
+	if len(o.instances) == 0 {
+		return fmt.Errorf("--instance must be set")
+	}
+	if len(o.clusterID) == 0 {
+		return fmt.Errorf("--cluster-id must be set")
+	}
+	if (len(o.adminCredentials) == 0) == (len(o.adminSecret) == 0) {
+		return fmt.Errorf("exactly one of --admin-credentials and --admin-secret must be set")
+	}
+
+	btpOperatorConfigmap, err := parseNamespacedName("--btp-operator-configmap", o.btpOperatorConfigmap)
+	if err != nil {
+		return err
+	}
+	var adminSecret *hanav1.NamespacedName
+	if len(o.adminSecret) > 0 {
+		secret, err := parseNamespacedName("--admin-secret", o.adminSecret)
+		if err != nil {
+			return err
+		}
+		adminSecret = &secret
+	}
+
+	c, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	listed := make([]instanceMappings, 0, len(o.instances))
+	for _, serviceInstanceID := range o.instances {
+		mappings, err := c.ListMappings(ctx, serviceInstanceID)
+		if err != nil {
+			return fmt.Errorf("failed to list the mappings of service instance %s: %w", serviceInstanceID, err)
+		}
+		listed = append(listed, instanceMappings{ServiceInstanceID: serviceInstanceID, Mappings: mappings})
+	}
+
+	generated, err := writeGenerated(os.Stdout, os.Stderr, o, listed, btpOperatorConfigmap, adminSecret)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "generated %d hanamapping(s) for cluster %s\n", generated, o.clusterID)
+	return nil
+}
+
+// writeGenerated writes the HANAMappings of runGenerate and returns their
+// number. An empty platform is kubernetes, like for the operator. Mappings of
+// the whole cluster, which have no namespace, are skipped with a warning to
+// errOut. If the admin secret lives in another namespace than some of the
+// HANAMappings, a HANAMappingCredentialGrant permitting their namespaces is
+// written first, as the webhook rejects the HANAMappings without it.
+func writeGenerated(w, errOut io.Writer, o *options, listed []instanceMappings, btpOperatorConfigmap hanav1.NamespacedName, adminSecret *hanav1.NamespacedName) (int, error) {
+	var hanaMappings []*hanav1.HANAMapping
+	for _, instance := range listed {
+		for _, mapping := range instance.Mappings {
+			platform := mapping.Platform
+			if len(platform) == 0 {
+				platform = hanav1.PlatformKubernetes
+			}
+			if platform != hanav1.PlatformKubernetes || mapping.PrimaryID != o.clusterID {
+				continue
+			}
+			if len(mapping.SecondaryID) == 0 {
+				fmt.Fprintf(errOut, "skipping the mapping of service instance %s to the whole cluster, a HANAMapping maps a single namespace\n", instance.ServiceInstanceID)
+				continue
+			}
+
+			hanaMappings = append(hanaMappings, &hanav1.HANAMapping{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: hanav1.GroupVersion.String(),
+					Kind:       "HANAMapping",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace:   mapping.SecondaryID,
+					Name:        o.namePrefix + "-" + instance.ServiceInstanceID,
+					Annotations: map[string]string{hanav1.AdoptAnnotation: "true"},
+				},
+				Spec: hanav1.HANAMappingSpec{
+					BTPOperatorConfigmap: btpOperatorConfigmap,
+					AdminAPIAccessSecret: adminSecret,
+					AdminCredentials:     o.adminCredentials,
+					Mapping: hanav1.Mapping{
+						ServiceInstanceID: instance.ServiceInstanceID,
+						TargetNamespace:   mapping.SecondaryID,
+						IsDefault:         mapping.IsDefault,
+					},
+				},
+			})
+		}
+	}
+
+	if adminSecret != nil {
+		if grant := credentialGrant(o.namePrefix, *adminSecret, hanaMappings); grant != nil {
+			if err := writeManifest(w, grant); err != nil {
+				return 0, err
+			}
+		}
+	}
+	for _, hanaMapping := range hanaMappings {
+		if err := writeManifest(w, hanaMapping); err != nil {
+			return 0, err
+		}
+	}
+	return len(hanaMappings), nil
+}
+
+// credentialGrant returns a HANAMappingCredentialGrant which permits the
+// namespaces of the HANAMappings to reference the admin secret, or nil if all
+// of them live in the namespace of the secret.
+func credentialGrant(namePrefix string, adminSecret hanav1.NamespacedName, hanaMappings []*hanav1.HANAMapping) *hanav1.HANAMappingCredentialGrant {
+	var from []hanav1.CredentialGrantFrom
+	seen := map[string]bool{adminSecret.Namespace: true}
+	for _, hanaMapping := range hanaMappings {
+		if seen[hanaMapping.Namespace] {
+			continue
+		}
+		seen[hanaMapping.Namespace] = true
+		from = append(from, hanav1.CredentialGrantFrom{Namespace: hanaMapping.Namespace})
+	}
+	if len(from) == 0 {
+		return nil
+	}
+
+	return &hanav1.HANAMappingCredentialGrant{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: hanav1.GroupVersion.String(),
+			Kind:       "HANAMappingCredentialGrant",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: adminSecret.Namespace,
+			Name:      namePrefix + "-" + adminSecret.Name,
+		},
+		Spec: hanav1.HANAMappingCredentialGrantSpec{
+			From: from,
+			To:   []hanav1.CredentialGrantTo{{Name: adminSecret.Name}},
+		},
+	}
+}
+
+// writeManifest writes obj as a YAML document without its status and the
+// fields the API server fills in.
+func writeManifest(w io.Writer, obj interface{}) error {
+	out, err := yaml.Marshal(obj)
+	if err != nil {
+		return err
+	}
+
+	manifest := map[string]interface{}{}
+	if err := yaml.Unmarshal(out, &manifest); err != nil {
+		return err
+	}
+	delete(manifest, "status")
+	if metadata, ok := manifest["metadata"].(map[string]interface{}); ok {
+		delete(metadata, "creationTimestamp")
+	}
+
+	if out, err = yaml.Marshal(manifest); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "---\n%s", out)
+	return err
+}
+
+func parseNamespacedName(flagName, value string) (hanav1.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(value, "/")
+	if !ok || len(namespace) == 0 || len(name) == 0 {
+		return hanav1.NamespacedName{}, fmt.Errorf("%s must be given as namespace/name", flagName)
+	}
+	return hanav1.NamespacedName{Namespace: namespace, Name: name}, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/yaml"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+var _ = Describe("generate", func() {
+	const serviceInstanceID = "2e1a6b4d-7c3f-4f8e-9a5b-1d2c3e4f5a6b"
+
+	var (
+		btpOperatorConfigmap = hanav1.NamespacedName{Namespace: "kyma-system", Name: "sap-btp-operator-config"}
+		listed               = []instanceMappings{{
+			ServiceInstanceID: serviceInstanceID,
+			Mappings: []inventory.Mapping{
+				{Platform: hanav1.PlatformKubernetes, PrimaryID: "cluster-1", SecondaryID: "team-a", IsDefault: true},
+				{PrimaryID: "cluster-1", SecondaryID: "team-b"},
+				{Platform: hanav1.PlatformKubernetes, PrimaryID: "cluster-2", SecondaryID: "team-c"},
+				{Platform: hanav1.PlatformCloudFoundry, PrimaryID: "cluster-1", SecondaryID: "space-guid"},
+				{Platform: hanav1.PlatformKubernetes, PrimaryID: "cluster-1"},
+			},
+		}}
+	)
+
+	// generate returns the kinds and namespace/names of the written manifests.
+	generate := func(adminCredentials string, adminSecret *hanav1.NamespacedName) ([]string, []map[string]interface{}) {
+		o := &options{clusterID: "cluster-1", namePrefix: "hana", adminCredentials: adminCredentials}
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		generated, err := writeGenerated(out, errOut, o, listed, btpOperatorConfigmap, adminSecret)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(generated).To(Equal(2))
+		Expect(errOut.String()).To(Equal("skipping the mapping of service instance " + serviceInstanceID + " to the whole cluster, a HANAMapping maps a single namespace\n"))
+
+		var objects []string
+		var manifests []map[string]interface{}
+		for _, document := range strings.Split(out.String(), "---\n")[1:] {
+			manifest := map[string]interface{}{}
+			Expect(yaml.Unmarshal([]byte(document), &manifest)).To(Succeed())
+			Expect(manifest).NotTo(HaveKey("status"))
+			metadata := manifest["metadata"].(map[string]interface{})
+			Expect(metadata).NotTo(HaveKey("creationTimestamp"))
+			objects = append(objects, manifest["kind"].(string)+" "+metadata["namespace"].(string)+"/"+metadata["name"].(string))
+			manifests = append(manifests, manifest)
+		}
+		return objects, manifests
+	}
+
+	DescribeTable("should generate the HANAMappings of the kubernetes mappings in the cluster",
+		func(adminCredentials string, adminSecret *hanav1.NamespacedName, expected []string) {
+			objects, _ := generate(adminCredentials, adminSecret)
+			Expect(objects).To(Equal(expected))
+		},
+		Entry("with admin credentials", "admin-credentials", nil, []string{
+			"HANAMapping team-a/hana-" + serviceInstanceID,
+			"HANAMapping team-b/hana-" + serviceInstanceID,
+		}),
+		Entry("with an admin secret in the namespace of a HANAMapping", "", &hanav1.NamespacedName{Namespace: "team-a", Name: "admin-api-access"}, []string{
+			"HANAMappingCredentialGrant team-a/hana-admin-api-access",
+			"HANAMapping team-a/hana-" + serviceInstanceID,
+			"HANAMapping team-b/hana-" + serviceInstanceID,
+		}),
+		Entry("with an admin secret in another namespace", "", &hanav1.NamespacedName{Namespace: "hana", Name: "admin-api-access"}, []string{
+			"HANAMappingCredentialGrant hana/hana-admin-api-access",
+			"HANAMapping team-a/hana-" + serviceInstanceID,
+			"HANAMapping team-b/hana-" + serviceInstanceID,
+		}),
+	)
+
+	It("should write the mapping, the adopt annotation and the grant", func() {
+		_, manifests := generate("", &hanav1.NamespacedName{Namespace: "team-a", Name: "admin-api-access"})
+		Expect(manifests).To(HaveLen(3))
+
+		Expect(manifests[0]["spec"]).To(Equal(map[string]interface{}{
+			"from": []interface{}{map[string]interface{}{"namespace": "team-b"}},
+			"to":   []interface{}{map[string]interface{}{"name": "admin-api-access"}},
+		}))
+
+		hanaMapping := &hanav1.HANAMapping{}
+		out, err := yaml.Marshal(manifests[1])
+		Expect(err).NotTo(HaveOccurred())
+		Expect(yaml.Unmarshal(out, hanaMapping)).To(Succeed())
+		Expect(hanaMapping.Annotations).To(HaveKeyWithValue(hanav1.AdoptAnnotation, "true"))
+		Expect(hanaMapping.Spec.BTPOperatorConfigmap).To(Equal(btpOperatorConfigmap))
+		Expect(hanaMapping.Spec.AdminAPIAccessSecret).To(Equal(&hanav1.NamespacedName{Namespace: "team-a", Name: "admin-api-access"}))
+		Expect(hanaMapping.Spec.Mapping).To(Equal(hanav1.Mapping{
+			ServiceInstanceID: serviceInstanceID,
+			TargetNamespace:   "team-a",
+			IsDefault:         true,
+		}))
+	})
+})
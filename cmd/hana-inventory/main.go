@@ -0,0 +1,237 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// hana-inventory manages the instance mappings of HANA Cloud service instances
+// with the same semantics as the operator, for environments without it.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const usage = `Manage the instance mappings of HANA Cloud service instances.
+
+Usage:
+  hana-inventory list --instance <ID> [-o table|json|yaml]
+  hana-inventory create --instance <ID> --primary-id <ID> --secondary-id <ID> [--platform kubernetes] [--default] [--owned]
+  hana-inventory delete --instance <ID> --primary-id <ID> --secondary-id <ID> [--platform kubernetes]
+  hana-inventory export --instance <ID> [--instance <ID> ...] [-o yaml|json]
+  hana-inventory import -f <file> [--owned]
+  hana-inventory generate --instance <ID> [--instance <ID> ...] --cluster-id <ID> (--admin-credentials <name> | --admin-secret <namespace/name>)
+
+The admin API access binding is read from a directory with the files baseurl
+and uaa, like a mounted admin API access secret (--binding-dir), or from a
+Kubernetes Secret manifest (--secret-file).
+
+Run "hana-inventory <command> -h" for the flags of a command.
+`
+
+type command struct {
+	run       func(ctx context.Context, o *options) error
+	configure func(fs *flag.FlagSet, o *options)
+}
+
+var commands = map[string]command{
+	"list":     {run: runList, configure: configureList},
+	"create":   {run: runCreate, configure: configureCreate},
+	"delete":   {run: runDelete, configure: configureDelete},
+	"export":   {run: runExport, configure: configureExport},
+	"import":   {run: runImport, configure: configureImport},
+	"generate": {run: runGenerate, configure: configureGenerate},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("hana-inventory "+os.Args[1], flag.ExitOnError)
+	o := &options{}
+	o.addBindingFlags(fs)
+	cmd.configure(fs, o)
+	_ = fs.Parse(os.Args[2:])
+	if fs.NArg() > 0 {
+		fmt.Fprintf(os.Stderr, "unexpected arguments %v\n\n%s", fs.Args(), usage)
+		os.Exit(2)
+	}
+
+	if err := cmd.run(context.Background(), o); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	bindingDir string
+	secretFile string
+	caBundle   string
+	proxyURL   string
+
+	instances   stringList
+	platform    string
+	primaryID   string
+	secondaryID string
+	isDefault   bool
+	owned       bool
+	output      string
+	file        string
+
+	clusterID            string
+	adminCredentials     string
+	adminSecret          string
+	btpOperatorConfigmap string
+	namePrefix           string
+}
+
+func (o *options) addBindingFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.bindingDir, "binding-dir", "", "Directory with the files baseurl and uaa of the admin API access binding.")
+	fs.StringVar(&o.secretFile, "secret-file", "", "Kubernetes Secret manifest of the admin API access binding, in YAML or JSON.")
+	fs.StringVar(&o.caBundle, "ca-bundle", "", "File with additional PEM encoded CA certificates to trust.")
+	fs.StringVar(&o.proxyURL, "proxy-url", "", "URL of an HTTP proxy to send the requests through.")
+}
+
+// client returns an inventory client for the configured binding.
+func (o *options) client() (inventory.Client, error) {
+	data := map[string][]byte{}
+	switch {
+	case len(o.bindingDir) > 0 && len(o.secretFile) > 0:
+		return nil, fmt.Errorf("only one of --binding-dir and --secret-file may be set")
+	case len(o.bindingDir) > 0:
+		for _, key := range []string{"baseurl", "uaa"} {
+			value, err := os.ReadFile(filepath.Join(o.bindingDir, key))
+			if err != nil {
+				return nil, err
+			}
+			data[key] = []byte(strings.TrimSpace(string(value)))
+		}
+	case len(o.secretFile) > 0:
+		manifest, err := os.ReadFile(o.secretFile)
+		if err != nil {
+			return nil, err
+		}
+		if data, err = secretData(manifest); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("one of --binding-dir and --secret-file must be set")
+	}
+
+	binding, err := inventory.BindingFromSecretData(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the admin API access binding: %w", err)
+	}
+	if len(o.caBundle) > 0 {
+		if binding.CABundle, err = os.ReadFile(o.caBundle); err != nil {
+			return nil, err
+		}
+	}
+	binding.ProxyURL = o.proxyURL
+
+	return inventory.NewClient(binding), nil
+}
+
+// secretData returns the data of a Secret manifest. Like the API server,
+// stringData wins over data.
+func secretData(manifest []byte) (map[string][]byte, error) {
+	secret := &corev1.Secret{}
+	if err := yaml.Unmarshal(manifest, secret); err != nil {
+		return nil, err
+	}
+
+	data := map[string][]byte{}
+	for key, value := range secret.Data {
+		data[key] = value
+	}
+	for key, value := range secret.StringData {
+		data[key] = []byte(value)
+	}
+	return data, nil
+}
+
+// stringList is a flag which may be given multiple times.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// instanceMappings is the format mappings are exported and imported in.
+type instanceMappings struct {
+	ServiceInstanceID string              `json:"serviceInstanceID"`
+	Mappings          []inventory.Mapping `json:"mappings"`
+}
+
+// readInstanceMappings reads mappings in the format written by export, in YAML
+// or JSON.
+func readInstanceMappings(content []byte) ([]instanceMappings, error) {
+	var imported []instanceMappings
+	if err := yaml.Unmarshal(content, &imported); err != nil {
+		return nil, err
+	}
+	return imported, nil
+}
+
+func writeOutput(w io.Writer, format string, v interface{}) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	case "yaml":
+		out, err := yaml.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	default:
+		return fmt.Errorf("unsupported output format %q", format)
+	}
+}
+
+func writeMappingsTable(w io.Writer, mappings []inventory.Mapping) error {
+	tw := tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+	fmt.Fprintln(tw, "PLATFORM\tPRIMARY ID\tSECONDARY ID\tDEFAULT")
+	for _, mapping := range mappings {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%t\n", mapping.Platform, mapping.PrimaryID, mapping.SecondaryID, mapping.IsDefault)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+// runResync bumps the resync annotation, which makes the operator reconcile
+// the HANAMapping right away.
+func runResync(ctx context.Context, o *options, args []string) error {
+	if err := annotate(ctx, o, args[0], hanav1.ResyncAnnotation, time.Now().UTC().Format(time.RFC3339Nano)); err != nil {
+		return err
+	}
+	fmt.Printf("hanamapping %s triggered for resync\n", args[0])
+	return nil
+}
+
+// runAdopt sets the adopt annotation, which makes the operator take over the
+// desired mapping if it exists in the inventory already.
+func runAdopt(ctx context.Context, o *options, args []string) error {
+	if err := annotate(ctx, o, args[0], hanav1.AdoptAnnotation, "true"); err != nil {
+		return err
+	}
+	fmt.Printf("hanamapping %s annotated for adoption\n", args[0])
+	return nil
+}
+
+func annotate(ctx context.Context, o *options, name, key, value string) error {
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	hanaMapping := &hanav1.HANAMapping{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, hanaMapping); err != nil {
+		return err
+	}
+
+	patch := client.MergeFrom(hanaMapping.DeepCopy())
+	if hanaMapping.Annotations == nil {
+		hanaMapping.Annotations = map[string]string{}
+	}
+	hanaMapping.Annotations[key] = value
+	return c.Patch(ctx, hanaMapping, patch, fieldOwner)
+}
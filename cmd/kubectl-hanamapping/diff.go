@@ -0,0 +1,100 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+)
+
+// runDiff compares the mapping the operator resolves for a HANAMapping with
+// the mappings recorded in its status and with the inventory. Mappings which
+// are missing are prefixed with +, mappings to be deleted with - and mappings
+// to be recreated with ~.
+func runDiff(ctx context.Context, o *options, args []string) error {
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	hanaMapping := &hanav1.HANAMapping{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: args[0]}, hanaMapping); err != nil {
+		return err
+	}
+
+	return printDiff(ctx, os.Stdout, c, o.clusterIDOptions(), hanaMapping)
+}
+
+// printDiff writes the differences of runDiff.
+func printDiff(ctx context.Context, w io.Writer, c client.Client, opts controller.ClusterIDOptions, hanaMapping *hanav1.HANAMapping) error {
+	desiredMappingID, err := controller.DesiredMappingID(ctx, c, opts, hanaMapping)
+	if err != nil {
+		return fmt.Errorf("failed to resolve the desired mapping: %w", err)
+	}
+
+	lookup := newInventoryLookup(c)
+	inSync := true
+
+	mapping, err := lookup.findMapping(ctx, hanaMapping, desiredMappingID)
+	if err != nil {
+		return err
+	}
+	switch {
+	case mapping == nil:
+		inSync = false
+		fmt.Fprintf(w, "+ %s (desired, missing in the inventory)\n", formatMappingID(desiredMappingID))
+	case mapping.IsDefault != hanaMapping.Spec.Mapping.IsDefault:
+		inSync = false
+		fmt.Fprintf(w, "~ %s (isDefault %t in the inventory, desired %t)\n", formatMappingID(desiredMappingID), mapping.IsDefault, hanaMapping.Spec.Mapping.IsDefault)
+	}
+
+	recorded := map[string]*hanav1.MappingID{
+		"recorded": controller.NormalizeMappingID(hanaMapping.Status.MappingID),
+		"pending":  controller.NormalizeMappingID(hanaMapping.Status.PendingMappingID),
+	}
+	for _, kind := range []string{"recorded", "pending"} {
+		mappingID := recorded[kind]
+		if mappingID == nil || *mappingID == *desiredMappingID {
+			continue
+		}
+		mapping, err := lookup.findMapping(ctx, hanaMapping, mappingID)
+		if err != nil {
+			return err
+		}
+		if mapping != nil {
+			inSync = false
+			fmt.Fprintf(w, "- %s (%s, no longer desired)\n", formatMappingID(mappingID), kind)
+		}
+	}
+
+	if desiredMappingID.Platform == hanav1.PlatformKubernetes && len(hanaMapping.Status.ClusterID) > 0 && hanaMapping.Status.ClusterID != desiredMappingID.PrimaryID {
+		inSync = false
+		fmt.Fprintf(w, "! cluster ID changed from %s to %s\n", hanaMapping.Status.ClusterID, desiredMappingID.PrimaryID)
+	}
+
+	if inSync {
+		fmt.Fprintf(w, "%s is in sync with the inventory\n", formatMappingID(desiredMappingID))
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+var _ = Describe("diff", func() {
+	var (
+		ctx  = context.Background()
+		opts = controller.ClusterIDOptions{ClusterID: testClusterID}
+		stub *inventoryStub
+	)
+
+	BeforeEach(func() {
+		stub = &inventoryStub{}
+		stubInventory(stub)
+	})
+
+	diff := func(hanaMapping *hanav1.HANAMapping) string {
+		out := &bytes.Buffer{}
+		Expect(printDiff(ctx, out, newFakeClient(), opts, hanaMapping)).To(Succeed())
+		return out.String()
+	}
+
+	It("should report a mapping in sync", func() {
+		stub.mappings = []inventory.Mapping{{Platform: hanav1.PlatformKubernetes, PrimaryID: testClusterID, SecondaryID: "team-a"}}
+
+		Expect(diff(newHANAMapping("test", hanav1.Mapping{TargetNamespace: "team-a"}))).To(Equal(
+			"kubernetes " + testClusterID + "/team-a is in sync with the inventory\n"))
+	})
+
+	It("should report a missing and a changed mapping", func() {
+		Expect(diff(newHANAMapping("test", hanav1.Mapping{TargetNamespace: "team-a"}))).To(Equal(
+			"+ kubernetes " + testClusterID + "/team-a (desired, missing in the inventory)\n"))
+
+		stub.mappings = []inventory.Mapping{{Platform: hanav1.PlatformKubernetes, PrimaryID: testClusterID, SecondaryID: "team-a"}}
+		Expect(diff(newHANAMapping("test", hanav1.Mapping{TargetNamespace: "team-a", IsDefault: true}))).To(Equal(
+			"~ kubernetes " + testClusterID + "/team-a (isDefault false in the inventory, desired true)\n"))
+	})
+
+	It("should report a recorded mapping which is no longer desired and a changed cluster ID", func() {
+		stub.mappings = []inventory.Mapping{
+			{Platform: hanav1.PlatformKubernetes, PrimaryID: testClusterID, SecondaryID: "team-a"},
+			{Platform: hanav1.PlatformKubernetes, PrimaryID: "cluster-0", SecondaryID: "team-a"},
+		}
+		hanaMapping := newHANAMapping("test", hanav1.Mapping{TargetNamespace: "team-a"})
+		hanaMapping.Status.ClusterID = "cluster-0"
+		hanaMapping.Status.MappingID = &hanav1.MappingID{ServiceInstanceID: testServiceInstanceID, PrimaryID: "cluster-0", SecondaryID: "team-a"}
+
+		Expect(diff(hanaMapping)).To(Equal(
+			"- kubernetes cluster-0/team-a (recorded, no longer desired)\n" +
+				"! cluster ID changed from cluster-0 to " + testClusterID + "\n"))
+	})
+})
@@ -0,0 +1,91 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+// runInstance prints all inventory mappings of a service instance together
+// with the HANAMapping or ClusterHANAMapping which owns them.
+func runInstance(ctx context.Context, o *options, args []string) error {
+	serviceInstanceID := args[0]
+
+	c, _, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := c.List(ctx, hanaMappings); err != nil {
+		return err
+	}
+	var instanceMapping *hanav1.HANAMapping
+	for i := range hanaMappings.Items {
+		if hanaMappings.Items[i].Spec.Mapping.ServiceInstanceID == serviceInstanceID {
+			instanceMapping = &hanaMappings.Items[i]
+			break
+		}
+	}
+
+	owners, err := controller.MappingOwners(ctx, c, serviceInstanceID)
+	if err != nil {
+		return err
+	}
+
+	var binding inventory.Binding
+	switch {
+	case len(o.secret) > 0:
+		namespace, name, ok := strings.Cut(o.secret, "/")
+		if !ok {
+			return fmt.Errorf("--secret must be given as namespace/name")
+		}
+		binding, err = controller.SecretAdminAPIAccessBinding(ctx, c, types.NamespacedName{Namespace: namespace, Name: name})
+	case instanceMapping != nil:
+		binding, err = controller.AdminAPIAccessBinding(ctx, c, instanceMapping)
+	default:
+		return fmt.Errorf("no hanamapping of service instance %s found, pass the admin API access secret by --secret", serviceInstanceID)
+	}
+	if err != nil {
+		return err
+	}
+
+	mappings, err := newInventoryClient(binding).ListMappings(ctx, serviceInstanceID)
+	if err != nil {
+		return err
+	}
+
+	w := newTabWriter(os.Stdout)
+	fmt.Fprintln(w, "PLATFORM\tPRIMARY ID\tSECONDARY ID\tDEFAULT\tOWNER")
+	for _, mapping := range mappings {
+		owner := owners[controller.InventoryMappingID(serviceInstanceID, mapping)]
+		if len(owner) == 0 {
+			owner = "<none>"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\n", mapping.Platform, mapping.PrimaryID, mapping.SecondaryID, mapping.IsDefault, owner)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,82 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+// newInventoryClient creates the client for the inventory of a binding, tests
+// replace it with a stub.
+var newInventoryClient = inventory.NewClient
+
+// inventoryLookup lists the mappings of a service instance once per admin API
+// access binding, so that listing many HANAMappings of the same instance only
+// calls the inventory once.
+type inventoryLookup struct {
+	c        client.Client
+	mappings map[string][]inventory.Mapping
+}
+
+func newInventoryLookup(c client.Client) *inventoryLookup {
+	return &inventoryLookup{c: c, mappings: map[string][]inventory.Mapping{}}
+}
+
+func (l *inventoryLookup) listMappings(ctx context.Context, hanaMapping *hanav1.HANAMapping, serviceInstanceID string) ([]inventory.Mapping, error) {
+	binding, err := controller.AdminAPIAccessBinding(ctx, l.c, hanaMapping)
+	if err != nil {
+		return nil, err
+	}
+
+	key := binding.BaseURL + "/" + binding.UAA.ClientID + "/" + serviceInstanceID
+	if mappings, ok := l.mappings[key]; ok {
+		return mappings, nil
+	}
+
+	mappings, err := newInventoryClient(binding).ListMappings(ctx, serviceInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	l.mappings[key] = mappings
+	return mappings, nil
+}
+
+// findMapping returns the inventory mapping with the ID of mappingID, or nil if
+// there is none.
+func (l *inventoryLookup) findMapping(ctx context.Context, hanaMapping *hanav1.HANAMapping, mappingID *hanav1.MappingID) (*inventory.Mapping, error) {
+	mappings, err := l.listMappings(ctx, hanaMapping, mappingID.ServiceInstanceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, mapping := range mappings {
+		if mapping.PrimaryID == mappingID.PrimaryID && mapping.SecondaryID == mappingID.SecondaryID {
+			return &mapping, nil
+		}
+	}
+	return nil, nil
+}
+
+func formatMappingID(mappingID *hanav1.MappingID) string {
+	return fmt.Sprintf("%s %s/%s", mappingID.Platform, mappingID.PrimaryID, mappingID.SecondaryID)
+}
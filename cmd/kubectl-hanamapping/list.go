@@ -0,0 +1,90 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+)
+
+// runList prints the HANAMappings with the mapping the operator resolves for
+// them and whether that mapping exists in the inventory.
+func runList(ctx context.Context, o *options, args []string) error {
+	c, namespace, err := o.client()
+	if err != nil {
+		return err
+	}
+
+	var listOptions []client.ListOption
+	if !o.allNamespaces {
+		listOptions = append(listOptions, client.InNamespace(namespace))
+	}
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := c.List(ctx, hanaMappings, listOptions...); err != nil {
+		return err
+	}
+
+	return printList(ctx, os.Stdout, c, o.clusterIDOptions(), hanaMappings.Items)
+}
+
+// printList writes the table of runList. The primary ID is the cluster ID and
+// the secondary ID the target namespace of kubernetes mappings.
+func printList(ctx context.Context, out io.Writer, c client.Client, opts controller.ClusterIDOptions, hanaMappings []hanav1.HANAMapping) error {
+	lookup := newInventoryLookup(c)
+	w := newTabWriter(out)
+	fmt.Fprintln(w, "NAMESPACE\tNAME\tSERVICE INSTANCE ID\tPLATFORM\tPRIMARY ID\tSECONDARY ID\tREADY\tINVENTORY")
+	for i := range hanaMappings {
+		hanaMapping := &hanaMappings[i]
+
+		ready := "Unknown"
+		if condition := meta.FindStatusCondition(hanaMapping.Status.Conditions, "Ready"); condition != nil {
+			ready = string(condition.Status)
+		}
+
+		mappingID, err := controller.DesiredMappingID(ctx, c, opts, hanaMapping)
+		if err != nil {
+			if hanaMapping.Status.MappingID == nil {
+				fmt.Fprintf(w, "%s\t%s\t%s\t\t\t\t%s\t%s\n", hanaMapping.Namespace, hanaMapping.Name, hanaMapping.Spec.Mapping.ServiceInstanceID, ready, err)
+				continue
+			}
+			mappingID = controller.NormalizeMappingID(hanaMapping.Status.MappingID)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", hanaMapping.Namespace, hanaMapping.Name, mappingID.ServiceInstanceID,
+			mappingID.Platform, mappingID.PrimaryID, mappingID.SecondaryID, ready, inventoryState(ctx, lookup, hanaMapping, mappingID))
+	}
+	return w.Flush()
+}
+
+func inventoryState(ctx context.Context, lookup *inventoryLookup, hanaMapping *hanav1.HANAMapping, mappingID *hanav1.MappingID) string {
+	mapping, err := lookup.findMapping(ctx, hanaMapping, mappingID)
+	if err != nil {
+		return "error: " + err.Error()
+	}
+	if mapping == nil {
+		return "missing"
+	}
+	return "mapped"
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+var _ = Describe("list", func() {
+	var (
+		ctx  = context.Background()
+		opts = controller.ClusterIDOptions{ClusterID: testClusterID}
+	)
+
+	BeforeEach(func() {
+		stubInventory(&inventoryStub{mappings: []inventory.Mapping{
+			{Platform: hanav1.PlatformKubernetes, PrimaryID: testClusterID, SecondaryID: "team-a"},
+		}})
+	})
+
+	It("should print the primary and secondary ID of each platform", func() {
+		kubernetesMapping := newHANAMapping("kubernetes", hanav1.Mapping{TargetNamespace: "team-a"})
+		kubernetesMapping.Status.Conditions = []metav1.Condition{{Type: "Ready", Status: metav1.ConditionTrue}}
+		cloudFoundryMapping := newHANAMapping("cloudfoundry", hanav1.Mapping{
+			Platform:    hanav1.PlatformCloudFoundry,
+			PrimaryID:   "org-guid",
+			SecondaryID: "space-guid",
+		})
+
+		out := &bytes.Buffer{}
+		Expect(printList(ctx, out, newFakeClient(), opts, []hanav1.HANAMapping{*kubernetesMapping, *cloudFoundryMapping})).To(Succeed())
+
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(3))
+		Expect(bytes.Fields(lines[0])).To(Equal(bytes.Fields([]byte("NAMESPACE NAME SERVICE INSTANCE ID PLATFORM PRIMARY ID SECONDARY ID READY INVENTORY"))))
+		Expect(bytes.Fields(lines[1])).To(Equal(bytes.Fields([]byte(testNamespace + " kubernetes " + testServiceInstanceID + " kubernetes " + testClusterID + " team-a True mapped"))))
+		Expect(bytes.Fields(lines[2])).To(Equal(bytes.Fields([]byte(testNamespace + " cloudfoundry " + testServiceInstanceID + " cloudfoundry org-guid space-guid Unknown missing"))))
+	})
+
+	It("should fall back to the recorded mapping ID if the desired one cannot be resolved", func() {
+		hanaMapping := newHANAMapping("kubernetes", hanav1.Mapping{TargetNamespace: "team-a"})
+		hanaMapping.Status.MappingID = &hanav1.MappingID{ServiceInstanceID: testServiceInstanceID, PrimaryID: testClusterID, SecondaryID: "team-a"}
+
+		out := &bytes.Buffer{}
+		Expect(printList(ctx, out, newFakeClient(), controller.ClusterIDOptions{}, []hanav1.HANAMapping{*hanaMapping})).To(Succeed())
+
+		lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+		Expect(lines).To(HaveLen(2))
+		Expect(bytes.Fields(lines[1])).To(Equal(bytes.Fields([]byte(testNamespace + " kubernetes " + testServiceInstanceID + " kubernetes " + testClusterID + " team-a Unknown mapped"))))
+	})
+})
@@ -0,0 +1,172 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// kubectl-hanamapping is a kubectl plugin to inspect and operate HANAMappings
+// and the instance mappings they maintain in the HANA Cloud inventory.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+)
+
+const fieldOwner = client.FieldOwner("kubectl-hanamapping")
+
+const usage = `Inspect and operate HANAMappings.
+
+Usage:
+  kubectl hanamapping list [-n namespace | -A]
+  kubectl hanamapping instance <service instance ID> [--secret namespace/name]
+  kubectl hanamapping diff <name> [-n namespace]
+  kubectl hanamapping resync <name> [-n namespace]
+  kubectl hanamapping adopt <name> [-n namespace]
+
+Commands:
+  list      List HANAMappings with their mapping ID and inventory state
+  instance  List all inventory mappings of a service instance
+  diff      Compare the desired, recorded and actual mapping of a HANAMapping
+  resync    Trigger an immediate reconciliation of a HANAMapping
+  adopt     Let a HANAMapping take over its mapping if it exists already
+
+Run "kubectl hanamapping <command> -h" for the flags of a command.
+`
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+
+	utilruntime.Must(hanav1.AddToScheme(scheme))
+}
+
+type command struct {
+	run func(ctx context.Context, o *options, args []string) error
+	// allNamespaces is set for commands which support the -A flag.
+	allNamespaces bool
+	args          int
+}
+
+var commands = map[string]command{
+	"list":     {run: runList, allNamespaces: true},
+	"instance": {run: runInstance, args: 1},
+	"diff":     {run: runDiff, args: 1},
+	"resync":   {run: runResync, args: 1},
+	"adopt":    {run: runAdopt, args: 1},
+}
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-h" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("kubectl hanamapping "+os.Args[1], flag.ExitOnError)
+	o := &options{}
+	o.addFlags(fs, cmd.allNamespaces)
+	if os.Args[1] == "instance" {
+		fs.StringVar(&o.secret, "secret", "", "Admin API access secret as namespace/name, defaults to the secret of a HANAMapping of the instance.")
+	}
+	_ = fs.Parse(os.Args[2:])
+
+	if fs.NArg() != cmd.args {
+		fmt.Fprintf(os.Stderr, "%s expects %d argument(s), got %d\n\n%s", os.Args[1], cmd.args, fs.NArg(), usage)
+		os.Exit(2)
+	}
+
+	if err := cmd.run(context.Background(), o, fs.Args()); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+type options struct {
+	kubeconfig      string
+	context         string
+	namespace       string
+	allNamespaces   bool
+	clusterID       string
+	clusterIDSource string
+	secret          string
+}
+
+func (o *options) addFlags(fs *flag.FlagSet, allNamespaces bool) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, defaults to the kubectl configuration.")
+	fs.StringVar(&o.context, "context", "", "Name of the kubeconfig context to use.")
+	fs.StringVar(&o.namespace, "namespace", "", "Namespace of the HANAMappings, defaults to the namespace of the context.")
+	fs.StringVar(&o.namespace, "n", "", "Shorthand for --namespace.")
+	fs.StringVar(&o.clusterID, "cluster-id", "", "Cluster ID the operator is configured with by --cluster-id, if any.")
+	fs.StringVar(&o.clusterIDSource, "cluster-id-source", "", "Cluster ID source the operator is configured with by --cluster-id-source, if any.")
+	if allNamespaces {
+		fs.BoolVar(&o.allNamespaces, "all-namespaces", false, "List the HANAMappings of all namespaces.")
+		fs.BoolVar(&o.allNamespaces, "A", false, "Shorthand for --all-namespaces.")
+	}
+}
+
+// client returns a client for the cluster of the kubeconfig context and the
+// namespace to work in.
+func (o *options) client() (client.Client, string, error) {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, &clientcmd.ConfigOverrides{CurrentContext: o.context})
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", err
+	}
+
+	namespace := o.namespace
+	if len(namespace) == 0 {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, "", err
+		}
+	}
+
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, "", err
+	}
+	return c, namespace, nil
+}
+
+func (o *options) clusterIDOptions() controller.ClusterIDOptions {
+	return controller.ClusterIDOptions{ClusterID: o.clusterID, Source: o.clusterIDSource}
+}
+
+func newTabWriter(w io.Writer) *tabwriter.Writer {
+	return tabwriter.NewWriter(w, 0, 8, 3, ' ', 0)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	testNamespace         = "test-namespace"
+	testSecretName        = "admin-api-access"
+	testServiceInstanceID = "2e1a6b4d-7c3f-4f8e-9a5b-1d2c3e4f5a6b"
+	testClusterID         = "cluster-1"
+)
+
+func TestKubectlHANAMapping(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "kubectl-hanamapping Suite")
+}
+
+// inventoryStub serves the mappings of a service instance from memory.
+type inventoryStub struct {
+	mappings []inventory.Mapping
+}
+
+func (s *inventoryStub) ListMappings(ctx context.Context, serviceInstanceID string) ([]inventory.Mapping, error) {
+	return s.mappings, nil
+}
+
+func (s *inventoryStub) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*inventory.Mapping, error) {
+	return nil, nil
+}
+
+func (s *inventoryStub) CreateMapping(ctx context.Context, serviceInstanceID string, mapping inventory.Mapping) error {
+	return nil
+}
+
+func (s *inventoryStub) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
+	return nil
+}
+
+// stubInventory lets the inventory lookups of the commands read from stub.
+func stubInventory(stub *inventoryStub) {
+	newInventoryClient = func(binding inventory.Binding) inventory.Client { return stub }
+	DeferCleanup(func() { newInventoryClient = inventory.NewClient })
+}
+
+func newFakeClient(objs ...client.Object) client.Client {
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: testSecretName},
+		Data: map[string][]byte{
+			"baseurl": []byte("https://api.example.com"),
+			"uaa":     []byte(`{"url":"https://uaa.example.com","clientid":"client","clientsecret":"secret"}`),
+		},
+	}
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objs, secret)...).Build()
+}
+
+func newHANAMapping(name string, mapping hanav1.Mapping) *hanav1.HANAMapping {
+	mapping.ServiceInstanceID = testServiceInstanceID
+	return &hanav1.HANAMapping{
+		ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+		Spec: hanav1.HANAMappingSpec{
+			Mapping:              mapping,
+			AdminAPIAccessSecret: &hanav1.NamespacedName{Namespace: testNamespace, Name: testSecretName},
+		},
+	}
+}
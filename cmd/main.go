@@ -25,16 +25,20 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	configv1alpha1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/config/v1alpha1"
 	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/config"
 	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
 	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
 	//+kubebuilder:scaffold:imports
@@ -53,20 +57,13 @@ func init() {
 }
 
 func main() {
-	var metricsAddr string
-	var enableLeaderElection bool
-	var probeAddr string
-	var secureMetrics bool
-	var enableHTTP2 bool
-	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
-	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
-		"Enable leader election for controller manager. "+
-			"Enabling this will ensure there is only one active controller manager.")
-	flag.BoolVar(&secureMetrics, "metrics-secure", false,
-		"If set the metrics endpoint is served securely")
-	flag.BoolVar(&enableHTTP2, "enable-http2", false,
-		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	var configFile string
+	defaults := &configv1alpha1.OperatorConfiguration{}
+	configv1alpha1.SetDefaults(defaults)
+	flag.StringVar(&configFile, "config", "",
+		"The path of an OperatorConfiguration file. Flags which are set explicitly take precedence over the file, "+
+			"changes of the inventory settings in the file are applied without a restart.")
+	bindFlags(flag.CommandLine, defaults)
 	opts := zap.Options{
 		Development: true,
 	}
@@ -75,6 +72,26 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	loader := &config.Loader{
+		Path:      configFile,
+		BindFlags: bindFlags,
+		Flags:     config.ExplicitFlags(flag.CommandLine),
+	}
+	cfg, err := loader.Load()
+	if err != nil {
+		setupLog.Error(err, "invalid configuration")
+		os.Exit(1)
+	}
+
+	clusterIDOptions := controller.ClusterIDOptions{
+		ClusterID:                   cfg.Defaults.ClusterID,
+		Source:                      cfg.Defaults.ClusterIDSource,
+		ConfigMapKey:                cfg.Defaults.ClusterIDConfigMapKey,
+		DefaultBTPOperatorConfigmap: cfg.Defaults.BTPOperatorConfigmap,
+	}
+	maxConcurrentReconciles := cfg.Controller.MaxConcurrentReconciles
+	operatorNamespace := cfg.Controller.OperatorNamespace
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancelation and
@@ -87,7 +104,7 @@ func main() {
 	}
 
 	tlsOpts := []func(*tls.Config){}
-	if !enableHTTP2 {
+	if !cfg.HTTP.EnableHTTP2 {
 		tlsOpts = append(tlsOpts, disableHTTP2)
 	}
 
@@ -98,13 +115,15 @@ func main() {
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
-			BindAddress:   metricsAddr,
-			SecureServing: secureMetrics,
+			BindAddress:   cfg.HTTP.MetricsBindAddress,
+			SecureServing: cfg.HTTP.SecureMetrics,
 			TLSOpts:       tlsOpts,
 		},
 		WebhookServer:          webhookServer,
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
+		Client:                 config.ClientOptions(),
+		Cache:                  config.CacheOptions(cfg),
+		HealthProbeBindAddress: cfg.HTTP.HealthProbeBindAddress,
+		LeaderElection:         cfg.Controller.LeaderElect,
 		LeaderElectionID:       "92b4d44c.cloud.sap.com",
 		// LeaderElectionReleaseOnCancel defines if the leader should step down voluntarily
 		// when the Manager ends. This requires the binary to immediately end when the
@@ -123,15 +142,148 @@ func main() {
 		os.Exit(1)
 	}
 
+	ttlClient, err := controller.NewTTLClient(mgr.GetClient(), mgr.GetAPIReader(), cfg.Resync.ObjectCacheTTL.Duration,
+		&corev1.ConfigMap{}, &corev1.Secret{})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+	if err = ttlClient.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to watch secrets and config maps")
+		os.Exit(1)
+	}
+
+	rateLimitedClientFactory := inventory.NewRateLimitedClientFactory(inventory.NewClient, cfg.Inventory.QPS, cfg.Inventory.Burst)
+	inventoryClientFactory := inventory.NewCircuitBreakerClientFactory(rateLimitedClientFactory.NewClient,
+		cfg.Inventory.FailureThreshold, cfg.Inventory.CircuitCooldown.Duration)
+	if len(configFile) > 0 {
+		if err = mgr.Add(&config.Watcher{
+			Loader:  loader,
+			Log:     ctrl.Log.WithName("config"),
+			Current: cfg,
+			OnChange: func(_, c *configv1alpha1.OperatorConfiguration) {
+				rateLimitedClientFactory.SetLimits(c.Inventory.QPS, c.Inventory.Burst)
+				inventoryClientFactory.SetFailureThreshold(c.Inventory.FailureThreshold, c.Inventory.CircuitCooldown.Duration)
+			},
+		}); err != nil {
+			setupLog.Error(err, "unable to watch configuration file")
+			os.Exit(1)
+		}
+	}
+
+	bindings := controller.NewBindingStore()
+	clusterIDMigrations := make(chan event.GenericEvent)
 	if err = (&controller.HANAMappingReconciler{
-		Client:             mgr.GetClient(),
-		Log:                ctrl.Log.WithName("controller").WithName("HANAMapping"),
-		Scheme:             mgr.GetScheme(),
-		GetInventoryClient: inventory.NewClient,
+		Client:                  ttlClient,
+		Log:                     ctrl.Log.WithName("controller").WithName("HANAMapping"),
+		Scheme:                  mgr.GetScheme(),
+		Recorder:                mgr.GetEventRecorderFor("hana-mapping-operator"),
+		GetInventoryClient:      inventoryClientFactory.NewClient,
+		Bindings:                bindings,
+		ClusterIDOptions:        clusterIDOptions,
+		DefaultDeletionPolicy:   cfg.GarbageCollection.DeletionPolicy,
+		ResyncInterval:          cfg.Resync.MappingResyncInterval.Duration,
+		ClusterIDMigrations:     clusterIDMigrations,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "HANAMapping")
 		os.Exit(1)
 	}
+	// ClusterHANAMappings are cluster-scoped and left to an operator watching all namespaces.
+	if len(cfg.Controller.WatchNamespaces) == 0 {
+		if err = (&controller.ClusterHANAMappingReconciler{
+			Client:                  ttlClient,
+			Log:                     ctrl.Log.WithName("controller").WithName("ClusterHANAMapping"),
+			Scheme:                  mgr.GetScheme(),
+			GetInventoryClient:      inventoryClientFactory.NewClient,
+			OperatorNamespace:       operatorNamespace,
+			ClusterIDOptions:        clusterIDOptions,
+			MaxConcurrentReconciles: maxConcurrentReconciles,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "ClusterHANAMapping")
+			os.Exit(1)
+		}
+	}
+	if err = (&controller.HANAAdminCredentialsReconciler{
+		Client:                  ttlClient,
+		Log:                     ctrl.Log.WithName("controller").WithName("HANAAdminCredentials"),
+		Scheme:                  mgr.GetScheme(),
+		ValidateBinding:         inventory.ValidateBinding,
+		Bindings:                bindings,
+		RevalidationInterval:    cfg.Resync.CredentialsRevalidationInterval.Duration,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HANAAdminCredentials")
+		os.Exit(1)
+	}
+	if err = (&controller.HANAInstanceInventoryReconciler{
+		Client:                  ttlClient,
+		Log:                     ctrl.Log.WithName("controller").WithName("HANAInstanceInventory"),
+		Scheme:                  mgr.GetScheme(),
+		GetInventoryClient:      inventoryClientFactory.NewClient,
+		Bindings:                bindings,
+		DefaultRefreshInterval:  cfg.Resync.InventoryRefreshInterval.Duration,
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HANAInstanceInventory")
+		os.Exit(1)
+	}
+	if cfg.FeatureEnabled(configv1alpha1.FeatureHANAMappingSet) {
+		if err = (&controller.HANAMappingSetReconciler{
+			Client:                  ttlClient,
+			Log:                     ctrl.Log.WithName("controller").WithName("HANAMappingSet"),
+			Scheme:                  mgr.GetScheme(),
+			MaxConcurrentReconciles: maxConcurrentReconciles,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "HANAMappingSet")
+			os.Exit(1)
+		}
+	}
+	if err = (&controller.HANAMappingOperatorReconciler{
+		Client:                  ttlClient,
+		Log:                     ctrl.Log.WithName("controller").WithName("HANAMappingOperator"),
+		Scheme:                  mgr.GetScheme(),
+		ClusterIDOptions:        clusterIDOptions,
+		ClusterHANAMappings:     len(cfg.Controller.WatchNamespaces) == 0,
+		HANAMappingSets:         cfg.FeatureEnabled(configv1alpha1.FeatureHANAMappingSet),
+		MaxConcurrentReconciles: maxConcurrentReconciles,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "HANAMappingOperator")
+		os.Exit(1)
+	}
+	if err = mgr.Add(&controller.ClusterIDMigrator{
+		Client:            ttlClient,
+		Log:               ctrl.Log.WithName("migrator").WithName("ClusterID"),
+		Recorder:          mgr.GetEventRecorderFor("hana-mapping-operator"),
+		Enqueue:           clusterIDMigrations,
+		ClusterIDOptions:  clusterIDOptions,
+		OperatorNamespace: operatorNamespace,
+		Interval:          cfg.Resync.ClusterIDMigrationInterval.Duration,
+		MaxParallelism:    cfg.Resync.ClusterIDMigrationParallelism,
+	}); err != nil {
+		setupLog.Error(err, "unable to add migrator", "migrator", "ClusterID")
+		os.Exit(1)
+	}
+	if cfg.FeatureEnabled(configv1alpha1.FeatureNamespaceController) {
+		if err = (&controller.NamespaceReconciler{
+			Client:                      ttlClient,
+			Log:                         ctrl.Log.WithName("controller").WithName("Namespace"),
+			Scheme:                      mgr.GetScheme(),
+			Recorder:                    mgr.GetEventRecorderFor("hana-mapping-operator"),
+			DefaultAdminAPIAccessSecret: cfg.Defaults.NamespaceAdminAPIAccessSecret,
+			WatchNamespaces:             cfg.Controller.WatchNamespaces,
+			MaxConcurrentReconciles:     maxConcurrentReconciles,
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "Namespace")
+			os.Exit(1)
+		}
+	}
+	if os.Getenv("ENABLE_WEBHOOKS") == "true" {
+		if err = (&hanav1.HANAMapping{}).SetupWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "HANAMapping")
+			os.Exit(1)
+		}
+	}
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
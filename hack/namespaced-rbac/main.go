@@ -0,0 +1,145 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// namespaced-rbac prints the RBAC of an operator restricted with
+// --watch-namespaces. It splits the generated manager ClusterRole into Roles
+// for the watched namespaces and a ClusterRole which only keeps the rules on
+// cluster-scoped resources. Applied after the deployment, it replaces the
+// rules of the cluster-wide ClusterRole of the same name.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterResources are read cluster-wide, events are created for cluster-scoped
+// objects in the default namespace.
+var clusterResources = map[string][]string{
+	"namespaces":                {"get", "list", "watch"},
+	"clusterhanamappings":       {"get", "list", "watch"},
+	"customresourcedefinitions": {"get"},
+	"events":                    nil,
+}
+
+func main() {
+	var rolePath, namespaces, operatorNamespace, btpOperatorNamespace, namePrefix string
+	flag.StringVar(&rolePath, "role", "config/rbac/role.yaml", "The manager ClusterRole generated by controller-gen.")
+	flag.StringVar(&namespaces, "namespaces", "", "A comma-separated list of the watched namespaces.")
+	flag.StringVar(&operatorNamespace, "operator-namespace", "hana-mapping-operator-system", "The namespace of the operator.")
+	flag.StringVar(&btpOperatorNamespace, "btp-operator-namespace", "kyma-system", "The namespace of the BTP operator config map.")
+	flag.StringVar(&namePrefix, "name-prefix", "hana-mapping-operator-", "The name prefix of the deployment.")
+	flag.Parse()
+
+	if err := run(rolePath, strings.Split(namespaces, ","), operatorNamespace, btpOperatorNamespace, namePrefix); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(rolePath string, namespaces []string, operatorNamespace, btpOperatorNamespace, namePrefix string) error {
+	data, err := os.ReadFile(rolePath)
+	if err != nil {
+		return err
+	}
+	clusterRole := &rbacv1.ClusterRole{}
+	if err := yaml.Unmarshal(data, clusterRole); err != nil {
+		return err
+	}
+
+	var clusterRules, namespacedRules []rbacv1.PolicyRule
+	for _, rule := range clusterRole.Rules {
+		for _, resource := range rule.Resources {
+			single := *rule.DeepCopy()
+			single.Resources = []string{resource}
+			if verbs, ok := clusterResources[resource]; ok {
+				if verbs != nil {
+					single.Verbs = verbs
+				}
+				clusterRules = append(clusterRules, single)
+				continue
+			}
+			if _, ok := clusterResources[strings.Split(resource, "/")[0]]; ok {
+				continue
+			}
+			namespacedRules = append(namespacedRules, single)
+		}
+	}
+
+	rules := map[string][]rbacv1.PolicyRule{}
+	for _, namespace := range namespaces {
+		if namespace = strings.TrimSpace(namespace); len(namespace) > 0 {
+			rules[namespace] = namespacedRules
+		}
+	}
+	if len(rules) == 0 {
+		return fmt.Errorf("--namespaces is required")
+	}
+	for namespace, resource := range map[string]string{btpOperatorNamespace: "configmaps", operatorNamespace: "secrets"} {
+		if _, watched := rules[namespace]; watched {
+			continue
+		}
+		for _, rule := range namespacedRules {
+			if rule.Resources[0] == resource {
+				rules[namespace] = append(rules[namespace], rule)
+			}
+		}
+	}
+
+	name := namePrefix + "manager-role"
+	objects := []interface{}{&rbacv1.ClusterRole{
+		TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Rules:      clusterRules,
+	}}
+	sorted := make([]string, 0, len(rules))
+	for namespace := range rules {
+		sorted = append(sorted, namespace)
+	}
+	sort.Strings(sorted)
+	for _, namespace := range sorted {
+		objects = append(objects, &rbacv1.Role{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+			Rules:      rules[namespace],
+		}, &rbacv1.RoleBinding{
+			TypeMeta:   metav1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: namePrefix + "manager-rolebinding"},
+			RoleRef:    rbacv1.RoleRef{APIGroup: rbacv1.GroupName, Kind: "Role", Name: name},
+			Subjects: []rbacv1.Subject{{
+				Kind:      rbacv1.ServiceAccountKind,
+				Namespace: operatorNamespace,
+				Name:      namePrefix + "controller-manager",
+			}},
+		})
+	}
+
+	for _, object := range objects {
+		data, err := yaml.Marshal(object)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("---\n%s", strings.ReplaceAll(string(data), "  creationTimestamp: null\n", ""))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package config
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	configv1alpha1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/config/v1alpha1"
+)
+
+// CacheOptions restricts the cache to the watched namespaces. The metadata of
+// ConfigMaps is also cached in the namespace of the default BTP operator config
+// map and the one of Secrets in the operator namespace, where
+// ClusterHANAMappings read them from. Of ConfigMaps and Secrets only the
+// metadata the operator reads is kept, see stripMetadata.
+func CacheOptions(c *configv1alpha1.OperatorConfiguration) cache.Options {
+	if len(c.Controller.WatchNamespaces) == 0 {
+		return cache.Options{
+			ByObject: map[client.Object]cache.ByObject{
+				&corev1.ConfigMap{}: {Transform: stripMetadata},
+				&corev1.Secret{}:    {Transform: stripMetadata},
+			},
+		}
+	}
+
+	return cache.Options{
+		DefaultNamespaces: cacheNamespaces(c.Controller.WatchNamespaces),
+		ByObject: map[client.Object]cache.ByObject{
+			&corev1.ConfigMap{}: {
+				Namespaces: cacheNamespaces(c.Controller.WatchNamespaces, c.Defaults.BTPOperatorConfigmap.Namespace),
+				Transform:  stripMetadata,
+			},
+			&corev1.Secret{}: {
+				Namespaces: cacheNamespaces(c.Controller.WatchNamespaces, c.Controller.OperatorNamespace),
+				Transform:  stripMetadata,
+			},
+		},
+	}
+}
+
+// stripMetadata drops the managed fields, annotations, labels and owner
+// references from the metadata of a cached object, the other metadata fields
+// are kept. The informers of ConfigMaps and Secrets only tell the TTL client
+// and the watches which objects changed, so that annotations like
+// last-applied-configuration and the managed fields need not be held in
+// memory for the whole cluster.
+func stripMetadata(obj interface{}) (interface{}, error) {
+	accessor, err := meta.Accessor(obj)
+	if err != nil {
+		// Tombstones of deleted objects are passed through.
+		return obj, nil
+	}
+	accessor.SetManagedFields(nil)
+	accessor.SetAnnotations(nil)
+	accessor.SetLabels(nil)
+	accessor.SetOwnerReferences(nil)
+	return obj, nil
+}
+
+// ClientOptions disables the cache for Secrets and ConfigMaps, so that they
+// are not held in memory for the whole cluster. The operator reads them
+// through a controller.TTLClient instead.
+func ClientOptions() client.Options {
+	return client.Options{
+		Cache: &client.CacheOptions{
+			DisableFor: []client.Object{&corev1.ConfigMap{}, &corev1.Secret{}},
+		},
+	}
+}
+
+func cacheNamespaces(namespaces []string, additional ...string) map[string]cache.Config {
+	configs := map[string]cache.Config{}
+	for _, namespace := range append(append([]string{}, namespaces...), additional...) {
+		if len(namespace) > 0 {
+			configs[namespace] = cache.Config{}
+		}
+	}
+	return configs
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+
+	configv1alpha1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/config/v1alpha1"
+)
+
+var _ = Describe("CacheOptions", func() {
+	var c *configv1alpha1.OperatorConfiguration
+
+	BeforeEach(func() {
+		c = &configv1alpha1.OperatorConfiguration{}
+		configv1alpha1.SetDefaults(c)
+		c.Controller.OperatorNamespace = "operator"
+	})
+
+	It("should cache all namespaces by default", func() {
+		options := CacheOptions(c)
+		Expect(options.DefaultNamespaces).To(BeNil())
+		Expect(options.ByObject).To(HaveLen(2))
+		for _, byObject := range options.ByObject {
+			Expect(byObject.Namespaces).To(BeNil())
+			Expect(byObject.Transform).NotTo(BeNil())
+		}
+	})
+
+	It("should restrict the cache to the watched namespaces", func() {
+		c.Controller.WatchNamespaces = []string{"team-a", "team-b"}
+		options := CacheOptions(c)
+		Expect(options.DefaultNamespaces).To(Equal(map[string]cache.Config{"team-a": {}, "team-b": {}}))
+
+		for object, byObject := range options.ByObject {
+			switch object.(type) {
+			case *corev1.ConfigMap:
+				Expect(byObject.Namespaces).To(Equal(map[string]cache.Config{"team-a": {}, "team-b": {}, "kyma-system": {}}))
+				Expect(byObject.Transform).NotTo(BeNil())
+			case *corev1.Secret:
+				Expect(byObject.Namespaces).To(Equal(map[string]cache.Config{"team-a": {}, "team-b": {}, "operator": {}}))
+				Expect(byObject.Transform).NotTo(BeNil())
+			default:
+				Fail("unexpected object")
+			}
+		}
+		Expect(options.ByObject).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("stripMetadata", func() {
+	It("should drop the managed fields, annotations, labels and owner references", func() {
+		obj := &metav1.PartialObjectMetadata{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "team-a",
+				Name:            "admin-api-access",
+				UID:             "0b6f3c5e-3f0a-4d5e-8c1b-2a9d7e4f6b10",
+				ResourceVersion: "42",
+				Labels:          map[string]string{"app": "hana"},
+				Annotations:     map[string]string{"kubectl.kubernetes.io/last-applied-configuration": "{}"},
+				ManagedFields:   []metav1.ManagedFieldsEntry{{Manager: "kubectl"}},
+				OwnerReferences: []metav1.OwnerReference{{Name: "owner"}},
+			},
+		}
+
+		transformed, err := stripMetadata(obj)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed).To(Equal(&metav1.PartialObjectMetadata{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "team-a", Name: "admin-api-access", UID: "0b6f3c5e-3f0a-4d5e-8c1b-2a9d7e4f6b10", ResourceVersion: "42"},
+		}))
+	})
+
+	It("should pass tombstones through", func() {
+		tombstone := toolscache.DeletedFinalStateUnknown{Key: "team-a/admin-api-access"}
+		transformed, err := stripMetadata(tombstone)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(transformed).To(Equal(tombstone))
+	})
+})
+
+var _ = Describe("ClientOptions", func() {
+	It("should not read secrets and config maps from the cache", func() {
+		options := ClientOptions()
+		Expect(options.Cache.DisableFor).To(ConsistOf(&corev1.ConfigMap{}, &corev1.Secret{}))
+	})
+})
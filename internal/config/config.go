@@ -0,0 +1,151 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+
+	configv1alpha1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/config/v1alpha1"
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/controller"
+)
+
+const kind = "OperatorConfiguration"
+
+// Loader reads the configuration file and applies the flags which were set
+// explicitly on top of it, so that flags take precedence over the file.
+type Loader struct {
+	// Path of the configuration file. Without a file only the defaults and flags apply.
+	Path string
+	// BindFlags binds the flags to the fields of a configuration.
+	BindFlags func(fs *flag.FlagSet, c *configv1alpha1.OperatorConfiguration)
+	// Flags are the explicitly set flags by name.
+	Flags map[string]string
+}
+
+// ExplicitFlags returns the values of the flags which were set on the command line.
+func ExplicitFlags(fs *flag.FlagSet) map[string]string {
+	flags := map[string]string{}
+	fs.Visit(func(f *flag.Flag) {
+		flags[f.Name] = f.Value.String()
+	})
+	return flags
+}
+
+// Load returns the defaulted and validated configuration.
+func (l *Loader) Load() (*configv1alpha1.OperatorConfiguration, error) {
+	c := &configv1alpha1.OperatorConfiguration{}
+	if len(l.Path) > 0 {
+		data, err := os.ReadFile(l.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err := yaml.UnmarshalStrict(data, c); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", l.Path, err)
+		}
+		if c.APIVersion != configv1alpha1.GroupVersion.String() || c.Kind != kind {
+			return nil, fmt.Errorf("%s must be a %s of %s", l.Path, kind, configv1alpha1.GroupVersion)
+		}
+	}
+	configv1alpha1.SetDefaults(c)
+
+	if l.BindFlags != nil {
+		fs := flag.NewFlagSet(kind, flag.ContinueOnError)
+		l.BindFlags(fs, c)
+		for name, value := range l.Flags {
+			if fs.Lookup(name) == nil {
+				continue
+			}
+			if err := fs.Set(name, value); err != nil {
+				return nil, fmt.Errorf("invalid flag --%s: %w", name, err)
+			}
+		}
+	}
+
+	if err := Validate(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Validate returns all invalid fields of the configuration.
+func Validate(c *configv1alpha1.OperatorConfiguration) error {
+	var errs field.ErrorList
+
+	defaults := field.NewPath("defaults")
+	errs = append(errs, validateNamespacedName(defaults.Child("btpOperatorConfigmap"), &c.Defaults.BTPOperatorConfigmap)...)
+	if c.Defaults.NamespaceAdminAPIAccessSecret != nil {
+		errs = append(errs, validateNamespacedName(defaults.Child("namespaceAdminAPIAccessSecret"), c.Defaults.NamespaceAdminAPIAccessSecret)...)
+	}
+	if err := controller.ValidateClusterIDSource(c.Defaults.ClusterIDSource); err != nil {
+		errs = append(errs, field.Invalid(defaults.Child("clusterIDSource"), c.Defaults.ClusterIDSource, err.Error()))
+	}
+
+	resync := field.NewPath("resync")
+	if c.Resync.InventoryRefreshInterval.Duration < 0 {
+		errs = append(errs, field.Invalid(resync.Child("inventoryRefreshInterval"), c.Resync.InventoryRefreshInterval.Duration, "must not be negative"))
+	}
+	if c.Resync.ClusterIDMigrationInterval.Duration < 0 {
+		errs = append(errs, field.Invalid(resync.Child("clusterIDMigrationInterval"), c.Resync.ClusterIDMigrationInterval.Duration, "must not be negative"))
+	}
+	if c.Resync.ClusterIDMigrationParallelism < 1 {
+		errs = append(errs, field.Invalid(resync.Child("clusterIDMigrationParallelism"), c.Resync.ClusterIDMigrationParallelism, "must be positive"))
+	}
+
+	inventory := field.NewPath("inventory")
+	if c.Inventory.Burst < 0 {
+		errs = append(errs, field.Invalid(inventory.Child("burst"), c.Inventory.Burst, "must not be negative"))
+	}
+	if c.Inventory.CircuitCooldown.Duration < 0 {
+		errs = append(errs, field.Invalid(inventory.Child("circuitCooldown"), c.Inventory.CircuitCooldown.Duration, "must not be negative"))
+	}
+
+	controllerPath := field.NewPath("controller")
+	if c.Controller.MaxConcurrentReconciles < 1 {
+		errs = append(errs, field.Invalid(controllerPath.Child("maxConcurrentReconciles"), c.Controller.MaxConcurrentReconciles, "must be positive"))
+	}
+	for i, namespace := range c.Controller.WatchNamespaces {
+		for _, msg := range validation.IsDNS1123Label(namespace) {
+			errs = append(errs, field.Invalid(controllerPath.Child("watchNamespaces").Index(i), namespace, msg))
+		}
+	}
+
+	switch c.GarbageCollection.DeletionPolicy {
+	case hanav1.DeletionPolicyDelete, hanav1.DeletionPolicyOrphan:
+	default:
+		errs = append(errs, field.NotSupported(field.NewPath("garbageCollection", "deletionPolicy"), c.GarbageCollection.DeletionPolicy,
+			[]string{hanav1.DeletionPolicyDelete, hanav1.DeletionPolicyOrphan}))
+	}
+
+	for feature := range c.FeatureGates {
+		if !configv1alpha1.KnownFeature(feature) {
+			errs = append(errs, field.Invalid(field.NewPath("featureGates").Key(feature), feature, "unknown feature gate"))
+		}
+	}
+
+	return errs.ToAggregate()
+}
+
+func validateNamespacedName(path *field.Path, name *hanav1.NamespacedName) field.ErrorList {
+	var errs field.ErrorList
+	if len(name.Namespace) == 0 {
+		errs = append(errs, field.Required(path.Child("namespace"), ""))
+	}
+	if len(name.Name) == 0 {
+		errs = append(errs, field.Required(path.Child("name"), ""))
+	}
+	return errs
+}
+
+// RestartRequired reports whether the configurations differ in more than the
+// inventory settings, which are the only ones applied without a restart.
+func RestartRequired(old, new *configv1alpha1.OperatorConfiguration) bool {
+	old, new = old.DeepCopy(), new.DeepCopy()
+	old.Inventory, new.Inventory = configv1alpha1.Inventory{}, configv1alpha1.Inventory{}
+	return !equality.Semantic.DeepEqual(old, new)
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	configv1alpha1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/config/v1alpha1"
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+func bindTestFlags(fs *flag.FlagSet, c *configv1alpha1.OperatorConfiguration) {
+	fs.Float64Var(&c.Inventory.QPS, "inventory-qps", c.Inventory.QPS, "")
+	fs.IntVar(&c.Controller.MaxConcurrentReconciles, "max-concurrent-reconciles", c.Controller.MaxConcurrentReconciles, "")
+}
+
+var _ = Describe("Loader", func() {
+	var (
+		path   string
+		loader *Loader
+	)
+
+	writeConfig := func(content string) {
+		Expect(os.WriteFile(path, []byte(content), 0o600)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		path = filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		loader = &Loader{Path: path, BindFlags: bindTestFlags}
+	})
+
+	It("should default an empty configuration", func() {
+		loader.Path = ""
+		c, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Defaults.BTPOperatorConfigmap).To(Equal(hanav1.NamespacedName{Namespace: "kyma-system", Name: "sap-btp-operator-config"}))
+		Expect(c.Inventory.QPS).To(Equal(5.0))
+		Expect(c.GarbageCollection.DeletionPolicy).To(Equal(hanav1.DeletionPolicyDelete))
+		Expect(c.FeatureEnabled(configv1alpha1.FeatureNamespaceController)).To(BeFalse())
+		Expect(c.FeatureEnabled(configv1alpha1.FeatureHANAMappingSet)).To(BeTrue())
+	})
+
+	It("should read the file and let explicit flags take precedence", func() {
+		writeConfig(`
+apiVersion: config.hana.cloud.sap.com/v1alpha1
+kind: OperatorConfiguration
+inventory:
+  qps: 2
+controller:
+  maxConcurrentReconciles: 4
+featureGates:
+  NamespaceController: true
+`)
+		loader.Flags = map[string]string{"inventory-qps": "7", "zap-log-level": "debug"}
+
+		c, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(c.Inventory.QPS).To(Equal(7.0))
+		Expect(c.Controller.MaxConcurrentReconciles).To(Equal(4))
+		Expect(c.FeatureEnabled(configv1alpha1.FeatureNamespaceController)).To(BeTrue())
+	})
+
+	It("should reject unknown fields and kinds", func() {
+		writeConfig(`
+apiVersion: config.hana.cloud.sap.com/v1alpha1
+kind: OperatorConfiguration
+inventory:
+  rate: 2
+`)
+		_, err := loader.Load()
+		Expect(err).To(MatchError(ContainSubstring("rate")))
+
+		writeConfig(`
+apiVersion: v1
+kind: ConfigMap
+`)
+		_, err = loader.Load()
+		Expect(err).To(MatchError(ContainSubstring("must be a OperatorConfiguration")))
+	})
+
+	It("should reject invalid settings", func() {
+		writeConfig(`
+apiVersion: config.hana.cloud.sap.com/v1alpha1
+kind: OperatorConfiguration
+defaults:
+  clusterIDSource: etcd
+controller:
+  watchNamespaces: [Team-A]
+garbageCollection:
+  deletionPolicy: Retain
+featureGates:
+  Unknown: true
+`)
+		_, err := loader.Load()
+		Expect(err).To(MatchError(ContainSubstring("controller.watchNamespaces[0]")))
+		Expect(err).To(MatchError(ContainSubstring("defaults.clusterIDSource")))
+		Expect(err).To(MatchError(ContainSubstring("garbageCollection.deletionPolicy")))
+		Expect(err).To(MatchError(ContainSubstring("featureGates[Unknown]")))
+	})
+
+	It("should only require a restart for changes other than the inventory settings", func() {
+		loader.Path = ""
+		old, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+
+		changed := old.DeepCopy()
+		changed.Inventory.QPS = 1
+		Expect(RestartRequired(old, changed)).To(BeFalse())
+
+		changed.Controller.MaxConcurrentReconciles = 2
+		Expect(RestartRequired(old, changed)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Watcher", func() {
+	It("should hand valid changes of the file to OnChange", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "config.yaml")
+		content := `
+apiVersion: config.hana.cloud.sap.com/v1alpha1
+kind: OperatorConfiguration
+inventory:
+  qps: %s
+`
+		write := func(qps string) {
+			Expect(os.WriteFile(path, []byte(fmt.Sprintf(content, qps)), 0o600)).To(Succeed())
+		}
+		write("2")
+
+		loader := &Loader{Path: path}
+		current, err := loader.Load()
+		Expect(err).NotTo(HaveOccurred())
+
+		changes := make(chan float64, 10)
+		watcher := &Watcher{
+			Loader:  loader,
+			Log:     logr.Discard(),
+			Current: current,
+			OnChange: func(_, c *configv1alpha1.OperatorConfiguration) {
+				changes <- c.Inventory.QPS
+			},
+		}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			defer GinkgoRecover()
+			Expect(watcher.Start(ctx)).To(Succeed())
+		}()
+
+		Eventually(func() float64 {
+			write("3")
+			select {
+			case qps := <-changes:
+				return qps
+			case <-time.After(100 * time.Millisecond):
+				return 0
+			}
+		}).Should(Equal(3.0))
+
+		write("invalid")
+		Consistently(changes, 300*time.Millisecond).ShouldNot(Receive())
+	})
+})
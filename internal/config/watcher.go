@@ -0,0 +1,79 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+
+	configv1alpha1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/config/v1alpha1"
+)
+
+// Watcher reloads the configuration file whenever it changes and hands valid
+// configurations to OnChange. Invalid files are logged and ignored, the last
+// valid configuration stays in effect.
+type Watcher struct {
+	Loader   *Loader
+	Log      logr.Logger
+	Current  *configv1alpha1.OperatorConfiguration
+	OnChange func(old, new *configv1alpha1.OperatorConfiguration)
+}
+
+// NeedLeaderElection makes every replica reload its configuration.
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start watches the directory of the configuration file until the context is
+// done. Mounted ConfigMaps are updated by swapping a symlink in the directory,
+// which is not observed when watching the file itself.
+func (w *Watcher) Start(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	if err := watcher.Add(filepath.Dir(w.Loader.Path)); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			w.reload()
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			w.Log.Error(err, "failed to watch configuration file", "path", w.Loader.Path)
+		}
+	}
+}
+
+func (w *Watcher) reload() {
+	c, err := w.Loader.Load()
+	if err != nil {
+		w.Log.Error(err, "ignoring invalid configuration file", "path", w.Loader.Path)
+		return
+	}
+	if equality.Semantic.DeepEqual(c, w.Current) {
+		return
+	}
+
+	w.Log.Info("reloaded configuration file", "path", w.Loader.Path)
+	if RestartRequired(w.Current, c) {
+		w.Log.Info("configuration changes other than inventory settings take effect after a restart")
+	}
+	if w.OnChange != nil {
+		w.OnChange(w.Current, c)
+	}
+	w.Current = c
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+// BindingStore holds the admin API access bindings the HANAAdminCredentials
+// reconciler resolved and validated, so that the controllers using the
+// credentials do not read their secret and service binding again. A binding
+// is only returned for the UID and resource version of the credentials it was
+// stored for. A nil BindingStore holds no bindings.
+type BindingStore struct {
+	mu      sync.RWMutex
+	entries map[types.NamespacedName]bindingEntry
+}
+
+type bindingEntry struct {
+	uid             types.UID
+	resourceVersion string
+	binding         inventory.Binding
+}
+
+// NewBindingStore returns an empty BindingStore.
+func NewBindingStore() *BindingStore {
+	return &BindingStore{entries: map[types.NamespacedName]bindingEntry{}}
+}
+
+// Get returns the binding stored for the given version of the credentials.
+func (s *BindingStore) Get(credentials *hanav1.HANAAdminCredentials) (inventory.Binding, bool) {
+	if s == nil {
+		return inventory.Binding{}, false
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	entry, ok := s.entries[client.ObjectKeyFromObject(credentials)]
+	if !ok || entry.uid != credentials.UID || entry.resourceVersion != credentials.ResourceVersion {
+		return inventory.Binding{}, false
+	}
+	return entry.binding, true
+}
+
+// Set stores the binding for the given version of the credentials.
+func (s *BindingStore) Set(credentials *hanav1.HANAAdminCredentials, binding inventory.Binding) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[client.ObjectKeyFromObject(credentials)] = bindingEntry{
+		uid:             credentials.UID,
+		resourceVersion: credentials.ResourceVersion,
+		binding:         binding,
+	}
+}
+
+// Delete drops the binding of the named credentials.
+func (s *BindingStore) Delete(name types.NamespacedName) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, name)
+}
@@ -0,0 +1,270 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	clusterFinalizerName = "clusterhanamappings.hana.cloud.sap.com/finalizer"
+)
+
+// ClusterHANAMappingReconciler reconciles a ClusterHANAMapping object. The
+// admin API access secret is only read from OperatorNamespace.
+type ClusterHANAMappingReconciler struct {
+	Client             client.Client
+	Log                logr.Logger
+	Scheme             *runtime.Scheme
+	GetInventoryClient func(adminAPIAccessBinding inventory.Binding) inventory.Client
+
+	OperatorNamespace       string
+	ClusterIDOptions        ClusterIDOptions
+	MaxConcurrentReconciles int
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterHANAMappingReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hanav1.ClusterHANAMapping{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=clusterhanamappings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=clusterhanamappings/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=clusterhanamappings/finalizers,verbs=update
+
+// Reconcile maps the service instance of a ClusterHANAMapping into all of its
+// target namespaces and removes the mappings of namespaces no longer listed.
+func (r *ClusterHANAMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("clusterhanamapping", req.Name).WithValues("correlation_id", uuid.New().String())
+
+	clusterMapping := &hanav1.ClusterHANAMapping{}
+	if err := r.Client.Get(ctx, req.NamespacedName, clusterMapping); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	log.Info(fmt.Sprintf("got clusterhanamapping gen %d", clusterMapping.Generation))
+	clusterMapping = clusterMapping.DeepCopy()
+
+	if !clusterMapping.ObjectMeta.DeletionTimestamp.IsZero() {
+		if controllerutil.ContainsFinalizer(clusterMapping, clusterFinalizerName) {
+			remainingMappingIDs, err := r.syncMappings(ctx, clusterMapping, nil)
+			if err != nil {
+				return r.handleSyncError(ctx, clusterMapping, remainingMappingIDs, err)
+			}
+			log.Info("deleted mappings")
+
+			if err := patchObject(ctx, r.Client, clusterMapping, func() {
+				controllerutil.RemoveFinalizer(clusterMapping, clusterFinalizerName)
+			}); err != nil {
+				return ctrl.Result{}, err
+			}
+			log.Info("removed finalizer")
+		}
+
+		return ctrl.Result{}, nil
+	}
+
+	if !controllerutil.ContainsFinalizer(clusterMapping, clusterFinalizerName) {
+		if err := patchObject(ctx, r.Client, clusterMapping, func() {
+			controllerutil.AddFinalizer(clusterMapping, clusterFinalizerName)
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("added finalizer")
+	}
+
+	if clusterMapping.Status.Conditions == nil {
+		if statusErr := r.setStatus(ctx, clusterMapping, metav1.ConditionFalse, conditionReasonInProgress, ""); statusErr != nil {
+			return ctrl.Result{}, statusErr
+		}
+		log.Info("initialized status")
+	}
+
+	clusterID, err := getClusterID(ctx, r.Client, r.ClusterIDOptions, "", clusterMapping.Spec.BTPOperatorConfigmap)
+	if err != nil {
+		return r.handleSyncError(ctx, clusterMapping, clusterMapping.Status.MappingIDs, err)
+	}
+
+	desiredMappingIDs := make([]hanav1.MappingID, 0, len(clusterMapping.Spec.TargetNamespaces))
+	for _, targetNamespace := range clusterMapping.Spec.TargetNamespaces {
+		desiredMappingIDs = append(desiredMappingIDs, hanav1.MappingID{
+			Platform:          hanav1.PlatformKubernetes,
+			ServiceInstanceID: clusterMapping.Spec.ServiceInstanceID,
+			PrimaryID:         clusterID,
+			SecondaryID:       targetNamespace,
+		})
+	}
+
+	mappingIDs, err := r.syncMappings(ctx, clusterMapping, desiredMappingIDs)
+	if err != nil {
+		return r.handleSyncError(ctx, clusterMapping, mappingIDs, err)
+	}
+	log.Info("synced mappings")
+
+	clusterMapping.Status.MappingIDs = mappingIDs
+	if statusErr := r.setStatus(ctx, clusterMapping, metav1.ConditionTrue, conditionReasonSucceeded, ""); statusErr != nil {
+		return ctrl.Result{}, statusErr
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// syncMappings deletes all recorded mappings which are not desired anymore and
+// creates the desired ones. Every create is journaled in the status before it
+// is sent, so that a mapping whose ID never made it into the status is still
+// known to be owned. It returns the mapping IDs which exist afterwards, also if
+// it fails half way, so that they can be recorded in the status.
+func (r *ClusterHANAMappingReconciler) syncMappings(ctx context.Context, clusterMapping *hanav1.ClusterHANAMapping, desiredMappingIDs []hanav1.MappingID) ([]hanav1.MappingID, error) {
+	currentMappingIDs := clusterMapping.Status.MappingIDs
+	if len(currentMappingIDs) == 0 && len(desiredMappingIDs) == 0 {
+		return nil, nil
+	}
+
+	adminAPIAccessBinding, err := getAdminAPIAccessBinding(ctx, r.Client, types.NamespacedName{Namespace: r.OperatorNamespace, Name: clusterMapping.Spec.AdminAPIAccessSecret})
+	if err != nil {
+		return currentMappingIDs, err
+	}
+
+	inventoryClient := r.GetInventoryClient(adminAPIAccessBinding)
+
+	owned := make(map[hanav1.MappingID]bool, len(currentMappingIDs))
+	for _, mappingID := range currentMappingIDs {
+		owned[*normalizeMappingID(&mappingID)] = true
+	}
+	desired := make(map[hanav1.MappingID]bool, len(desiredMappingIDs))
+	for _, mappingID := range desiredMappingIDs {
+		desired[mappingID] = true
+	}
+
+	if err := replayOperations(ctx, r.Client, clusterMapping, &clusterMapping.Status.Operations, inventoryClient,
+		func(mappingID *hanav1.MappingID) bool {
+			return desired[*mappingID] || owned[*mappingID]
+		},
+		func(*hanav1.MappingID) {}); err != nil {
+		return currentMappingIDs, err
+	}
+	for _, operation := range clusterMapping.Status.Operations {
+		if operation.Type == hanav1.InventoryOperationCreate {
+			owned[*normalizeMappingID(&operation.MappingID)] = true
+		}
+	}
+
+	mappingIDs := make([]hanav1.MappingID, 0, len(currentMappingIDs)+len(desiredMappingIDs))
+	var syncErr error
+
+	for _, mappingID := range currentMappingIDs {
+		mappingID := *normalizeMappingID(&mappingID)
+		if desired[mappingID] {
+			continue
+		}
+		if syncErr == nil {
+			syncErr = deleteInventoryMapping(ctx, inventoryClient, &mappingID)
+			if syncErr == nil {
+				continue
+			}
+		}
+		mappingIDs = append(mappingIDs, mappingID)
+	}
+
+	for _, mappingID := range desiredMappingIDs {
+		mappingID := mappingID
+		if syncErr == nil && !owned[mappingID] {
+			syncErr = r.recordCreate(ctx, clusterMapping, inventoryClient, &mappingID)
+			if syncErr == nil {
+				owned[mappingID] = true
+			}
+		}
+		if syncErr == nil {
+			syncErr = createInventoryMapping(ctx, inventoryClient, &mappingID, false, owned[mappingID])
+			if syncErr == nil {
+				mappingIDs = append(mappingIDs, mappingID)
+				continue
+			}
+		}
+		if owned[mappingID] && !inventory.IsMappingRemoved(syncErr) {
+			mappingIDs = append(mappingIDs, mappingID)
+		}
+	}
+
+	return mappingIDs, syncErr
+}
+
+// recordCreate journals the create of a mapping in the status, unless the
+// mapping exists already and would be taken over.
+func (r *ClusterHANAMappingReconciler) recordCreate(ctx context.Context, clusterMapping *hanav1.ClusterHANAMapping, inventoryClient inventory.Client, mappingID *hanav1.MappingID) error {
+	if err := ensureInventoryMappingNotExists(ctx, inventoryClient, mappingID); err != nil {
+		return err
+	}
+	return patchStatus(ctx, r.Client, clusterMapping, func() {
+		if !hasOperation(clusterMapping.Status.Operations, hanav1.InventoryOperationCreate, mappingID) {
+			clusterMapping.Status.Operations = append(clusterMapping.Status.Operations, newInventoryOperation(hanav1.InventoryOperationCreate, mappingID))
+		}
+	})
+}
+
+func (r *ClusterHANAMappingReconciler) handleSyncError(ctx context.Context, clusterMapping *hanav1.ClusterHANAMapping, mappingIDs []hanav1.MappingID, err error) (ctrl.Result, error) {
+	clusterMapping.Status.MappingIDs = mappingIDs
+	if statusErr := r.setStatus(ctx, clusterMapping, metav1.ConditionFalse, failureReason(err), err.Error()); statusErr != nil {
+		r.Log.Error(statusErr, "failed to record sync error", "clusterhanamapping", clusterMapping.Name)
+	}
+	if err == inventory.ErrCircuitOpen {
+		return ctrl.Result{RequeueAfter: inventoryUnavailableRequeueInterval}, nil
+	}
+	return ctrl.Result{}, err
+}
+
+func (r *ClusterHANAMappingReconciler) setStatus(ctx context.Context, clusterMapping *hanav1.ClusterHANAMapping, status metav1.ConditionStatus, reason, message string) error {
+	condition := metav1.Condition{
+		Type:               conditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: clusterMapping.Generation,
+	}
+	mappingIDs := clusterMapping.Status.MappingIDs
+	return patchStatus(ctx, r.Client, clusterMapping, func() {
+		meta.SetStatusCondition(&clusterMapping.Status.Conditions, condition)
+		clusterMapping.Status.MappingIDs = mappingIDs
+		// the journal only has to remember the mappings not recorded above
+		for _, mappingID := range mappingIDs {
+			clusterMapping.Status.Operations = removeOperations(clusterMapping.Status.Operations, normalizeMappingID(&mappingID))
+		}
+	})
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	clusterhanamappingName = "test-clusterhanamapping"
+)
+
+var _ = Describe("ClusterHANAMapping Controller", func() {
+	var (
+		log logr.Logger
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		log = ctrl.Log.WithName("test-log")
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		clusterMapping := &hanav1.ClusterHANAMapping{}
+		err := k8sClient.Get(ctx, types.NamespacedName{Name: clusterhanamappingName}, clusterMapping)
+		if err == nil {
+			clusterMapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, clusterMapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, clusterMapping)).To(Succeed())
+		}
+	})
+
+	It("should map the service instance into all target namespaces", func() {
+		clusterMapping := newClusterHANAMapping(clusterhanamappingName)
+		Expect(k8sClient.Create(ctx, clusterMapping)).To(Succeed())
+
+		inventoryClientStub := &inventoryClientStub{}
+
+		controllerReconciler := &ClusterHANAMappingReconciler{
+			Client:             k8sClient,
+			Log:                log,
+			Scheme:             k8sClient.Scheme(),
+			GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			OperatorNamespace:  testNamespace,
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: clusterhanamappingName},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventoryClientStub.createdMappings).To(HaveLen(2))
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: clusterhanamappingName}, clusterMapping)).To(Succeed())
+		Expect(clusterMapping.Status.MappingIDs).To(HaveLen(2))
+		Expect(clusterMapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonSucceeded))
+	})
+
+	It("should remove the mapping of a namespace which is no longer listed", func() {
+		clusterMapping := newClusterHANAMapping(clusterhanamappingName)
+		clusterMapping.Spec.TargetNamespaces = []string{"team-a"}
+		Expect(k8sClient.Create(ctx, clusterMapping)).To(Succeed())
+
+		clusterMapping.Status.Conditions = []metav1.Condition{{
+			LastTransitionTime: metav1.Now(),
+			Type:               conditionTypeReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             conditionReasonSucceeded,
+		}}
+		clusterMapping.Status.MappingIDs = []hanav1.MappingID{
+			{Platform: hanav1.PlatformKubernetes, ServiceInstanceID: hanamappingServiceInstanceID, PrimaryID: clusterID, SecondaryID: "team-a"},
+			{Platform: hanav1.PlatformKubernetes, ServiceInstanceID: hanamappingServiceInstanceID, PrimaryID: clusterID, SecondaryID: "team-b"},
+		}
+		Expect(k8sClient.Status().Update(ctx, clusterMapping)).To(Succeed())
+
+		inventoryClientStub := &inventoryClientStub{}
+		inventoryClientStub.ListMappingsReturns([]inventory.Mapping{
+			{Platform: hanav1.PlatformKubernetes, PrimaryID: clusterID, SecondaryID: "team-a"},
+			{Platform: hanav1.PlatformKubernetes, PrimaryID: clusterID, SecondaryID: "team-b"},
+		}, nil)
+
+		controllerReconciler := &ClusterHANAMappingReconciler{
+			Client:             k8sClient,
+			Log:                log,
+			Scheme:             k8sClient.Scheme(),
+			GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			OperatorNamespace:  testNamespace,
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: clusterhanamappingName},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventoryClientStub.deletedMappings).To(Equal(1))
+		Expect(inventoryClientStub.createdMappings).To(BeEmpty())
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: clusterhanamappingName}, clusterMapping)).To(Succeed())
+		Expect(clusterMapping.Status.MappingIDs).To(HaveLen(1))
+		Expect(clusterMapping.Status.MappingIDs[0].SecondaryID).To(Equal("team-a"))
+	})
+
+	It("should fail to read a secret outside of the operator namespace", func() {
+		clusterMapping := newClusterHANAMapping(clusterhanamappingName)
+		Expect(k8sClient.Create(ctx, clusterMapping)).To(Succeed())
+
+		controllerReconciler := &ClusterHANAMappingReconciler{
+			Client:             k8sClient,
+			Log:                log,
+			Scheme:             k8sClient.Scheme(),
+			GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return &inventoryClientStub{} },
+			OperatorNamespace:  "default",
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: clusterhanamappingName},
+		})
+
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should adopt the journaled mappings if recording them failed", func() {
+		clusterMapping := newClusterHANAMapping(clusterhanamappingName)
+		Expect(k8sClient.Create(ctx, clusterMapping)).To(Succeed())
+
+		inventoryClientStub := &inventoryClientStub{}
+
+		controllerReconciler := &ClusterHANAMappingReconciler{
+			Client:             &mappingIDsStatusFailingClient{Client: k8sClient},
+			Log:                log,
+			Scheme:             k8sClient.Scheme(),
+			GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			OperatorNamespace:  testNamespace,
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: clusterhanamappingName},
+		})
+
+		Expect(err).To(HaveOccurred())
+		Expect(inventoryClientStub.createdMappings).To(HaveLen(2))
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: clusterhanamappingName}, clusterMapping)).To(Succeed())
+		Expect(clusterMapping.Status.MappingIDs).To(BeEmpty())
+		Expect(clusterMapping.Status.Operations).To(HaveLen(2))
+		Expect(clusterMapping.Status.Operations[0].Type).To(Equal(hanav1.InventoryOperationCreate))
+
+		// the mappings exist now, they must be taken over instead of failing as foreign
+		inventoryClientStub.ListMappingsReturns(inventoryClientStub.createdMappings, nil)
+		controllerReconciler.Client = k8sClient
+
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: clusterhanamappingName},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(inventoryClientStub.createdMappings).To(HaveLen(2))
+
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: clusterhanamappingName}, clusterMapping)).To(Succeed())
+		Expect(clusterMapping.Status.MappingIDs).To(HaveLen(2))
+		Expect(clusterMapping.Status.Operations).To(BeEmpty())
+		Expect(clusterMapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonSucceeded))
+	})
+})
+
+// mappingIDsStatusFailingClient fails every status write which records the
+// mapping IDs of a ClusterHANAMapping.
+type mappingIDsStatusFailingClient struct {
+	client.Client
+}
+
+func (c *mappingIDsStatusFailingClient) Status() client.SubResourceWriter {
+	return &mappingIDsStatusFailingWriter{SubResourceWriter: c.Client.Status()}
+}
+
+type mappingIDsStatusFailingWriter struct {
+	client.SubResourceWriter
+}
+
+func (w *mappingIDsStatusFailingWriter) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if clusterMapping, ok := obj.(*hanav1.ClusterHANAMapping); ok && len(clusterMapping.Status.MappingIDs) > 0 {
+		return fmt.Errorf("status write of %s failed", clusterMapping.Name)
+	}
+	return w.SubResourceWriter.Patch(ctx, obj, patch, opts...)
+}
+
+func newClusterHANAMapping(name string) *hanav1.ClusterHANAMapping {
+	return &hanav1.ClusterHANAMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Spec: hanav1.ClusterHANAMappingSpec{
+			BTPOperatorConfigmap: hanav1.NamespacedName{
+				Namespace: testNamespace,
+				Name:      btpOperatorConfigmap,
+			},
+			AdminAPIAccessSecret: adminAPIAccessSecret,
+			ServiceInstanceID:    hanamappingServiceInstanceID,
+			TargetNamespaces:     []string{"team-a", "team-b"},
+		},
+	}
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+const (
+	// ClusterIDSourceConfigMap reads the cluster ID from the BTP operator config map.
+	ClusterIDSourceConfigMap = "configmap"
+	// ClusterIDSourceKubeSystem uses the UID of the kube-system namespace as cluster ID.
+	ClusterIDSourceKubeSystem = "kube-system"
+
+	DefaultClusterIDConfigMapKey = "CLUSTER_ID"
+
+	conditionReasonClusterIDMissing = "ClusterIDMissing"
+)
+
+var clusterIDPattern = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9._-]{0,251}[a-zA-Z0-9])?$`)
+
+// ClusterIDOptions configure how the cluster ID of kubernetes mappings is
+// resolved. An explicit ClusterID takes precedence over the Source.
+type ClusterIDOptions struct {
+	ClusterID    string
+	Source       string
+	ConfigMapKey string
+	// DefaultBTPOperatorConfigmap is read if the mapping does not reference a
+	// config map, it defaults to kyma-system/sap-btp-operator-config.
+	DefaultBTPOperatorConfigmap hanav1.NamespacedName
+}
+
+// ValidateClusterIDSource returns an error for unknown sources.
+func ValidateClusterIDSource(source string) error {
+	switch source {
+	case "", ClusterIDSourceConfigMap, ClusterIDSourceKubeSystem:
+		return nil
+	default:
+		return fmt.Errorf("unsupported cluster ID source %q, must be %s or %s", source, ClusterIDSourceConfigMap, ClusterIDSourceKubeSystem)
+	}
+}
+
+// getClusterID resolves the cluster ID of a mapping. The override of the
+// mapping wins over the options, an empty or malformed cluster ID fails with
+// the ClusterIDMissing reason instead of creating a mapping without primary ID.
+func getClusterID(ctx context.Context, c client.Client, opts ClusterIDOptions, override string, btpOperatorConfigmap hanav1.NamespacedName) (string, error) {
+	clusterID, source := override, "spec.clusterID"
+	if len(clusterID) == 0 && len(opts.ClusterID) > 0 {
+		clusterID, source = opts.ClusterID, "--cluster-id"
+	}
+
+	if len(clusterID) == 0 {
+		var err error
+		switch opts.Source {
+		case ClusterIDSourceKubeSystem:
+			source = "namespace kube-system"
+			clusterID, err = getKubeSystemUID(ctx, c)
+		default:
+			source, clusterID, err = getConfigMapClusterID(ctx, c, opts, btpOperatorConfigmap)
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if len(clusterID) == 0 {
+		return "", &conditionError{
+			reason:  conditionReasonClusterIDMissing,
+			message: fmt.Sprintf("cluster ID is missing in %s", source),
+		}
+	}
+	if !clusterIDPattern.MatchString(clusterID) {
+		return "", &conditionError{
+			reason:  conditionReasonClusterIDMissing,
+			message: fmt.Sprintf("cluster ID %q in %s is malformed", clusterID, source),
+		}
+	}
+	return clusterID, nil
+}
+
+func getConfigMapClusterID(ctx context.Context, c client.Client, opts ClusterIDOptions, btpOperatorConfigmap hanav1.NamespacedName) (string, string, error) {
+	if len(btpOperatorConfigmap.Namespace) == 0 && len(btpOperatorConfigmap.Name) == 0 {
+		btpOperatorConfigmap = opts.DefaultBTPOperatorConfigmap
+	}
+
+	cmNamespace := btpOperatorConfigmap.Namespace
+	if len(cmNamespace) == 0 {
+		cmNamespace = defaultBTPOperatorConfigmapNamespace
+	}
+
+	cmName := btpOperatorConfigmap.Name
+	if len(cmName) == 0 {
+		cmName = defaultBTPOperatorConfigmapName
+	}
+
+	key := opts.ConfigMapKey
+	if len(key) == 0 {
+		key = DefaultClusterIDConfigMapKey
+	}
+
+	source := fmt.Sprintf("key %s of configmap %s/%s", key, cmNamespace, cmName)
+
+	cm := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: cmNamespace, Name: cmName}, cm); err != nil {
+		return source, "", err
+	}
+
+	return source, cm.Data[key], nil
+}
+
+func getKubeSystemUID(ctx context.Context, c client.Client) (string, error) {
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: metav1.NamespaceSystem}, ns); err != nil {
+		return "", err
+	}
+	return string(ns.UID), nil
+}
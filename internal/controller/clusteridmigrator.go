@@ -0,0 +1,265 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+const (
+	conditionTypeClusterIDMigration = "ClusterIDMigration"
+
+	conditionReasonMigrating = "Migrating"
+	conditionReasonMigrated  = "Migrated"
+
+	eventReasonClusterIDMigrationStarted   = "ClusterIDMigrationStarted"
+	eventReasonClusterIDMigrationCompleted = "ClusterIDMigrationCompleted"
+	eventReasonClusterIDMigrationFailed    = "ClusterIDMigrationFailed"
+)
+
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// ClusterIDMigrator periodically compares the cluster ID of all kubernetes
+// HANAMappings with the one they were mapped with. If it changed, e.g. after
+// Kyma was re-provisioned, it marks at most MaxParallelism HANAMappings at a
+// time as migrating in their ClusterIDMigration condition and enqueues them.
+// The HANAMappingReconciler then moves them to the new cluster ID with its
+// journaled remap, which creates and verifies the new mapping before the old
+// one is deleted. Progress is reported as events on the namespace of the
+// operator.
+type ClusterIDMigrator struct {
+	Client   client.Client
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// Enqueue receives the HANAMappings whose migration was started, it is
+	// consumed by the HANAMappingReconciler.
+	Enqueue chan<- event.GenericEvent
+
+	ClusterIDOptions  ClusterIDOptions
+	OperatorNamespace string
+	Interval          time.Duration
+	MaxParallelism    int
+
+	// migrating holds the HANAMappings seen migrating, so that their
+	// completion is reported once.
+	migrating map[types.NamespacedName]bool
+	// failed holds the migrating HANAMappings seen failing, so that their
+	// failure is reported once. They are left to the retries of the
+	// HANAMappingReconciler and do not hold up further migrations.
+	failed map[types.NamespacedName]bool
+}
+
+// NeedLeaderElection makes sure only the leading manager migrates mappings.
+func (m *ClusterIDMigrator) NeedLeaderElection() bool {
+	return true
+}
+
+// Start runs the migration every Interval until the context is done.
+func (m *ClusterIDMigrator) Start(ctx context.Context) error {
+	wait.UntilWithContext(ctx, func(ctx context.Context) {
+		if err := m.migrate(ctx); err != nil {
+			m.Log.Error(err, "failed to migrate hanamappings to the new cluster ID")
+		}
+	}, m.Interval)
+	return nil
+}
+
+type clusterIDMigration struct {
+	hanaMapping  *hanav1.HANAMapping
+	oldMappingID hanav1.MappingID
+	newClusterID string
+}
+
+// migrate reports the progress of the running migrations and starts as many
+// pending ones as MaxParallelism allows.
+func (m *ClusterIDMigrator) migrate(ctx context.Context) error {
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := m.Client.List(ctx, hanaMappings); err != nil {
+		return err
+	}
+	if m.migrating == nil {
+		m.migrating = map[types.NamespacedName]bool{}
+	}
+	if m.failed == nil {
+		m.failed = map[types.NamespacedName]bool{}
+	}
+
+	var (
+		running   []*hanav1.HANAMapping
+		completed int
+		failed    []string
+		pending   []clusterIDMigration
+	)
+	seen := map[types.NamespacedName]bool{}
+	for i := range hanaMappings.Items {
+		hanaMapping := &hanaMappings.Items[i]
+		key := client.ObjectKeyFromObject(hanaMapping)
+		seen[key] = true
+
+		if meta.IsStatusConditionTrue(hanaMapping.Status.Conditions, conditionTypeClusterIDMigration) {
+			m.migrating[key] = true
+			if ready := meta.FindStatusCondition(hanaMapping.Status.Conditions, conditionTypeReady); ready != nil &&
+				ready.Status == metav1.ConditionFalse && ready.Reason != conditionReasonInProgress {
+				if !m.failed[key] {
+					m.failed[key] = true
+					failed = append(failed, key.String())
+				}
+				continue
+			}
+			delete(m.failed, key)
+			running = append(running, hanaMapping)
+			continue
+		}
+		if m.migrating[key] {
+			delete(m.migrating, key)
+			delete(m.failed, key)
+			if meta.IsStatusConditionPresentAndEqual(hanaMapping.Status.Conditions, conditionTypeClusterIDMigration, metav1.ConditionFalse) {
+				completed++
+			}
+		}
+
+		if migration, ok := m.pendingMigration(ctx, hanaMapping); ok {
+			pending = append(pending, migration)
+		}
+	}
+	for key := range m.migrating {
+		if !seen[key] {
+			delete(m.migrating, key)
+			delete(m.failed, key)
+		}
+	}
+
+	if completed > 0 {
+		m.event(corev1.EventTypeNormal, eventReasonClusterIDMigrationCompleted,
+			fmt.Sprintf("migrated %d hanamappings to a new cluster ID", completed))
+	}
+	if len(failed) > 0 {
+		m.event(corev1.EventTypeWarning, eventReasonClusterIDMigrationFailed,
+			fmt.Sprintf("failed to migrate %d more hanamappings to a new cluster ID: %v", len(failed), failed))
+	}
+
+	// Running migrations are enqueued again in case their HANAMapping was
+	// never reconciled, e.g. because the operator restarted.
+	for _, hanaMapping := range running {
+		if err := m.enqueue(ctx, hanaMapping); err != nil {
+			return err
+		}
+	}
+
+	parallelism := m.MaxParallelism
+	if parallelism <= 0 {
+		parallelism = 1
+	}
+	var started int
+	for _, migration := range pending {
+		if len(running)+started >= parallelism {
+			break
+		}
+		if err := m.startMigration(ctx, migration); err != nil {
+			m.Log.Error(err, "failed to start migration", "hanamapping", client.ObjectKeyFromObject(migration.hanaMapping))
+			continue
+		}
+		m.migrating[client.ObjectKeyFromObject(migration.hanaMapping)] = true
+		started++
+	}
+
+	if started > 0 {
+		m.event(corev1.EventTypeNormal, eventReasonClusterIDMigrationStarted,
+			fmt.Sprintf("migrating %d of %d hanamappings to a new cluster ID", started, len(pending)))
+	}
+	return nil
+}
+
+// pendingMigration reports whether the resolved cluster ID of a kubernetes
+// HANAMapping differs from the primary ID it was mapped with. HANAMappings
+// with an explicit cluster ID or in the middle of another remap are left to
+// the HANAMappingReconciler.
+func (m *ClusterIDMigrator) pendingMigration(ctx context.Context, hanaMapping *hanav1.HANAMapping) (clusterIDMigration, bool) {
+	oldMappingID := normalizeMappingID(hanaMapping.Status.MappingID)
+	if oldMappingID == nil || oldMappingID.Platform != hanav1.PlatformKubernetes || len(hanaMapping.Spec.ClusterID) > 0 ||
+		!hanaMapping.DeletionTimestamp.IsZero() || hanaMapping.Status.PendingMappingID != nil || len(hanaMapping.Status.Operations) > 0 {
+		return clusterIDMigration{}, false
+	}
+
+	clusterID, err := getClusterID(ctx, m.Client, m.ClusterIDOptions, hanaMapping.Spec.ClusterID, hanaMapping.Spec.BTPOperatorConfigmap)
+	if err != nil {
+		m.Log.Error(err, "failed to resolve cluster ID", "hanamapping", client.ObjectKeyFromObject(hanaMapping))
+		return clusterIDMigration{}, false
+	}
+	if clusterID == oldMappingID.PrimaryID {
+		return clusterIDMigration{}, false
+	}
+
+	return clusterIDMigration{
+		hanaMapping:  hanaMapping,
+		oldMappingID: *oldMappingID,
+		newClusterID: clusterID,
+	}, true
+}
+
+// startMigration marks the HANAMapping as migrating, which lets the
+// HANAMappingReconciler remap it, and enqueues it.
+func (m *ClusterIDMigrator) startMigration(ctx context.Context, migration clusterIDMigration) error {
+	hanaMapping := migration.hanaMapping
+	condition := metav1.Condition{
+		Type:    conditionTypeClusterIDMigration,
+		Status:  metav1.ConditionTrue,
+		Reason:  conditionReasonMigrating,
+		Message: fmt.Sprintf("migrating from cluster ID %s to %s", migration.oldMappingID.PrimaryID, migration.newClusterID),
+	}
+	if err := patchStatus(ctx, m.Client, hanaMapping, func() {
+		meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
+	}); err != nil {
+		return err
+	}
+	return m.enqueue(ctx, hanaMapping)
+}
+
+func (m *ClusterIDMigrator) enqueue(ctx context.Context, hanaMapping *hanav1.HANAMapping) error {
+	if m.Enqueue == nil {
+		return nil
+	}
+	select {
+	case m.Enqueue <- event.GenericEvent{Object: hanaMapping}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (m *ClusterIDMigrator) event(eventType, reason, message string) {
+	m.Log.Info(message, "reason", reason)
+	if m.Recorder == nil || len(m.OperatorNamespace) == 0 {
+		return
+	}
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: m.OperatorNamespace}}
+	m.Recorder.Event(namespace, eventType, reason, message)
+}
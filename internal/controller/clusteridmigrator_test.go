@@ -0,0 +1,279 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const oldClusterID = "test-oldclusterid"
+
+var _ = Describe("ClusterID Migrator", func() {
+	var (
+		log      logr.Logger
+		ctx      context.Context
+		recorder *record.FakeRecorder
+		enqueued chan event.GenericEvent
+		migrator *ClusterIDMigrator
+		names    []string
+	)
+
+	BeforeEach(func() {
+		log = ctrl.Log.WithName("test-log")
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		enqueued = make(chan event.GenericEvent, 10)
+		migrator = &ClusterIDMigrator{
+			Client:            k8sClient,
+			Log:               log,
+			Recorder:          recorder,
+			Enqueue:           enqueued,
+			OperatorNamespace: testNamespace,
+			MaxParallelism:    2,
+		}
+		names = nil
+	})
+
+	AfterEach(func() {
+		for _, name := range names {
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, hanamapping)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		}
+	})
+
+	createMappedHANAMapping := func(name, primaryID string) *hanav1.HANAMapping {
+		hanamapping := newHANAMapping(name)
+		Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+		names = append(names, name)
+
+		hanamapping.Status.Conditions = []metav1.Condition{{
+			LastTransitionTime: metav1.Now(),
+			Type:               conditionTypeReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             conditionReasonSucceeded,
+		}}
+		hanamapping.Status.MappingID = &hanav1.MappingID{
+			Platform:          hanav1.PlatformKubernetes,
+			ServiceInstanceID: hanamappingServiceInstanceID,
+			PrimaryID:         primaryID,
+			SecondaryID:       hanamappingTargetNamespace,
+		}
+		Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+		return hanamapping
+	}
+
+	getHANAMapping := func(name string) *hanav1.HANAMapping {
+		hanamapping := &hanav1.HANAMapping{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, hanamapping)).To(Succeed())
+		return hanamapping
+	}
+
+	It("should start the migration of a mapping to the new cluster ID", func() {
+		createMappedHANAMapping(hanamappingName, oldClusterID)
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+
+		hanamapping := getHANAMapping(hanamappingName)
+		Expect(hanamapping.Status.MappingID.PrimaryID).To(Equal(oldClusterID))
+		condition := meta.FindStatusCondition(hanamapping.Status.Conditions, conditionTypeClusterIDMigration)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(conditionReasonMigrating))
+
+		var e event.GenericEvent
+		Expect(enqueued).To(Receive(&e))
+		Expect(e.Object.GetName()).To(Equal(hanamappingName))
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationStarted)))
+	})
+
+	It("should not touch a mapping of the current cluster ID", func() {
+		createMappedHANAMapping(hanamappingName, clusterID)
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+
+		hanamapping := getHANAMapping(hanamappingName)
+		Expect(meta.FindStatusCondition(hanamapping.Status.Conditions, conditionTypeClusterIDMigration)).To(BeNil())
+		Expect(enqueued).NotTo(Receive())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should not start more migrations than allowed in parallel", func() {
+		for i := 0; i < 3; i++ {
+			createMappedHANAMapping(fmt.Sprintf("%s-%d", hanamappingName, i), oldClusterID)
+		}
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(enqueued).To(HaveLen(2))
+
+		// The running migrations are enqueued again, but no further one is started.
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(enqueued).To(HaveLen(4))
+
+		var migrating int
+		for _, name := range names {
+			if meta.IsStatusConditionTrue(getHANAMapping(name).Status.Conditions, conditionTypeClusterIDMigration) {
+				migrating++
+			}
+		}
+		Expect(migrating).To(Equal(2))
+	})
+
+	It("should report a migration completed by the reconciler", func() {
+		createMappedHANAMapping(hanamappingName, oldClusterID)
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationStarted)))
+
+		hanamapping := getHANAMapping(hanamappingName)
+		hanamapping.Status.MappingID.PrimaryID = clusterID
+		meta.SetStatusCondition(&hanamapping.Status.Conditions, metav1.Condition{
+			Type:   conditionTypeClusterIDMigration,
+			Status: metav1.ConditionFalse,
+			Reason: conditionReasonMigrated,
+		})
+		Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationCompleted)))
+	})
+
+	It("should report a migration which fails in the reconciler", func() {
+		createMappedHANAMapping(hanamappingName, oldClusterID)
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationStarted)))
+
+		hanamapping := getHANAMapping(hanamappingName)
+		meta.SetStatusCondition(&hanamapping.Status.Conditions, metav1.Condition{
+			Type:   conditionTypeReady,
+			Status: metav1.ConditionFalse,
+			Reason: conditionReasonFailed,
+		})
+		Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationFailed)))
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+
+	It("should start the next migration when one fails", func() {
+		migrator.MaxParallelism = 1
+		first := fmt.Sprintf("%s-%d", hanamappingName, 0)
+		second := fmt.Sprintf("%s-%d", hanamappingName, 1)
+		createMappedHANAMapping(first, oldClusterID)
+		createMappedHANAMapping(second, oldClusterID)
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationStarted)))
+		var e event.GenericEvent
+		Expect(enqueued).To(Receive(&e))
+		Expect(enqueued).NotTo(Receive())
+
+		failing, waiting := e.Object.GetName(), second
+		if failing == second {
+			waiting = first
+		}
+		Expect(meta.IsStatusConditionTrue(getHANAMapping(waiting).Status.Conditions, conditionTypeClusterIDMigration)).To(BeFalse())
+
+		hanamapping := getHANAMapping(failing)
+		meta.SetStatusCondition(&hanamapping.Status.Conditions, metav1.Condition{
+			Type:   conditionTypeReady,
+			Status: metav1.ConditionFalse,
+			Reason: conditionReasonFailed,
+		})
+		Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationFailed)))
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonClusterIDMigrationStarted)))
+		Expect(enqueued).To(Receive(&e))
+		Expect(e.Object.GetName()).To(Equal(waiting))
+		Expect(meta.IsStatusConditionTrue(getHANAMapping(waiting).Status.Conditions, conditionTypeClusterIDMigration)).To(BeTrue())
+
+		By("reporting the failure only once")
+		Expect(migrator.migrate(ctx)).To(Succeed())
+		Expect(recorder.Events).NotTo(Receive())
+	})
+})
+
+// mappingStoreStub keeps the mappings of a single service instance, so that
+// created mappings can be read back.
+type mappingStoreStub struct {
+	mu        sync.Mutex
+	mappings  map[string]inventory.Mapping
+	created   int
+	createErr error
+}
+
+func (c *mappingStoreStub) ListMappings(ctx context.Context, serviceInstanceID string) ([]inventory.Mapping, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	mappings := make([]inventory.Mapping, 0, len(c.mappings))
+	for _, mapping := range c.mappings {
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+func (c *mappingStoreStub) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*inventory.Mapping, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	mapping, ok := c.mappings[primaryID+"/"+secondaryID]
+	if !ok {
+		return nil, inventory.ErrMappingNotFound
+	}
+	return &mapping, nil
+}
+
+func (c *mappingStoreStub) CreateMapping(ctx context.Context, serviceInstanceID string, mapping inventory.Mapping) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.createErr != nil {
+		return c.createErr
+	}
+	c.created++
+	c.mappings[mapping.PrimaryID+"/"+mapping.SecondaryID] = mapping
+	return nil
+}
+
+func (c *mappingStoreStub) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.mappings, primaryID+"/"+secondaryID)
+	return nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	conditionReasonTokenRequestFailed = "TokenRequestFailed"
+)
+
+// HANAAdminCredentialsReconciler reconciles a HANAAdminCredentials object
+type HANAAdminCredentialsReconciler struct {
+	Client          client.Client
+	Log             logr.Logger
+	Scheme          *runtime.Scheme
+	ValidateBinding func(ctx context.Context, binding inventory.Binding) error
+	// Bindings receives the validated bindings for the controllers which use
+	// the credentials.
+	Bindings *BindingStore
+	// RevalidationInterval is how often ready credentials fetch a token again,
+	// so that revoked credentials are noticed. It is disabled if not positive.
+	RevalidationInterval time.Duration
+
+	MaxConcurrentReconciles int
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HANAAdminCredentialsReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hanav1.HANAAdminCredentials{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(r.credentialsForSecret), builder.OnlyMetadata).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanaadmincredentials,verbs=get;list;watch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanaadmincredentials/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=services.cloud.sap.com,resources=servicebindings,verbs=get
+
+// Reconcile reads the admin API access binding of a HANAAdminCredentials,
+// validates it by fetching a token and publishes it in the binding store.
+func (r *HANAAdminCredentialsReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("hanaadmincredentials", req.NamespacedName).WithValues("correlation_id", uuid.New().String())
+
+	credentials := &hanav1.HANAAdminCredentials{}
+	if err := r.Client.Get(ctx, req.NamespacedName, credentials); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		r.Bindings.Delete(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+	log.Info(fmt.Sprintf("got hanaadmincredentials gen %d", credentials.Generation))
+	credentials = credentials.DeepCopy()
+
+	binding, secretName, err := getAdminCredentialsBinding(ctx, r.Client, credentials)
+	if err != nil {
+		return ctrl.Result{}, r.setStatusFailed(ctx, credentials, secretName, failureReason(err), err)
+	}
+
+	if err := r.ValidateBinding(ctx, binding); err != nil {
+		return ctrl.Result{}, r.setStatusFailed(ctx, credentials, secretName, conditionReasonTokenRequestFailed, err)
+	}
+	log.Info("validated credentials")
+
+	condition := metav1.Condition{
+		Type:               conditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             conditionReasonSucceeded,
+		ObservedGeneration: credentials.Generation,
+	}
+	if err := patchStatus(ctx, r.Client, credentials, func() {
+		meta.SetStatusCondition(&credentials.Status.Conditions, condition)
+		credentials.Status.SecretName = secretName
+	}); err != nil {
+		return ctrl.Result{}, err
+	}
+	// The binding is stored for the resource version written by the patch,
+	// which is the one the consumers see next.
+	r.Bindings.Set(credentials, binding)
+
+	return ctrl.Result{RequeueAfter: r.RevalidationInterval}, nil
+}
+
+// credentialsForSecret enqueues the HANAAdminCredentials which last read their
+// binding from a changed secret, so that rotated credentials are validated.
+func (r *HANAAdminCredentialsReconciler) credentialsForSecret(ctx context.Context, obj client.Object) []reconcile.Request {
+	credentialsList := &hanav1.HANAAdminCredentialsList{}
+	if err := r.Client.List(ctx, credentialsList, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list hanaadmincredentials for secret", "secret", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, credentials := range credentialsList.Items {
+		if credentials.Status.SecretName != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&credentials)})
+	}
+	return requests
+}
+
+// setStatusFailed records the failure in the Ready condition and returns the
+// error, so that the credentials are retried with backoff. A failure to record
+// the status is only logged, so that it does not mask the error.
+func (r *HANAAdminCredentialsReconciler) setStatusFailed(ctx context.Context, credentials *hanav1.HANAAdminCredentials, secretName, reason string, err error) error {
+	r.Bindings.Delete(client.ObjectKeyFromObject(credentials))
+
+	condition := metav1.Condition{
+		Type:               conditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            err.Error(),
+		ObservedGeneration: credentials.Generation,
+	}
+	if statusErr := patchStatus(ctx, r.Client, credentials, func() {
+		meta.SetStatusCondition(&credentials.Status.Conditions, condition)
+		credentials.Status.SecretName = secretName
+	}); statusErr != nil {
+		r.Log.Error(statusErr, "failed to record error", "hanaadmincredentials", client.ObjectKeyFromObject(credentials))
+	}
+	return err
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	adminCredentialsName = "test-admincredentials"
+)
+
+var _ = Describe("HANAAdminCredentials Controller", func() {
+	var (
+		log logr.Logger
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		log = ctrl.Log.WithName("test-log")
+		ctx = context.Background()
+	})
+
+	AfterEach(func() {
+		credentials := &hanav1.HANAAdminCredentials{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName}, credentials); err == nil {
+			Expect(k8sClient.Delete(ctx, credentials)).To(Succeed())
+		}
+	})
+
+	It("should mark valid credentials as ready", func() {
+		Expect(k8sClient.Create(ctx, newHANAAdminCredentials(adminCredentialsName))).To(Succeed())
+
+		var validatedBinding inventory.Binding
+		bindings := NewBindingStore()
+		controllerReconciler := &HANAAdminCredentialsReconciler{
+			Client:   k8sClient,
+			Log:      log,
+			Scheme:   k8sClient.Scheme(),
+			Bindings: bindings,
+			ValidateBinding: func(ctx context.Context, binding inventory.Binding) error {
+				validatedBinding = binding
+				return nil
+			},
+			RevalidationInterval: 10 * time.Minute,
+		}
+
+		result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+		Expect(validatedBinding.BaseURL).To(Equal(adminAPIAccessBaseURL))
+		Expect(validatedBinding.ProxyURL).To(Equal("http://proxy:3128"))
+
+		credentials := &hanav1.HANAAdminCredentials{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName}, credentials)).To(Succeed())
+		Expect(credentials.Status.SecretName).To(Equal(adminAPIAccessSecret))
+		Expect(credentials.Status.Conditions[0].Status).Should(Equal(metav1.ConditionTrue))
+
+		storedBinding, ok := bindings.Get(credentials)
+		Expect(ok).To(BeTrue())
+		Expect(storedBinding).To(Equal(validatedBinding))
+	})
+
+	It("should report credentials which fail to fetch a token", func() {
+		credentials := newHANAAdminCredentials(adminCredentialsName)
+		Expect(k8sClient.Create(ctx, credentials)).To(Succeed())
+
+		bindings := NewBindingStore()
+		bindings.Set(credentials, inventory.Binding{BaseURL: adminAPIAccessBaseURL})
+		controllerReconciler := &HANAAdminCredentialsReconciler{
+			Client:   k8sClient,
+			Log:      log,
+			Scheme:   k8sClient.Scheme(),
+			Bindings: bindings,
+			ValidateBinding: func(ctx context.Context, binding inventory.Binding) error {
+				return errors.New("invalid client credentials")
+			},
+		}
+
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName},
+		})
+
+		Expect(err).To(HaveOccurred())
+
+		credentials = &hanav1.HANAAdminCredentials{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName}, credentials)).To(Succeed())
+		Expect(credentials.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+		Expect(credentials.Status.Conditions[0].Reason).Should(Equal(conditionReasonTokenRequestFailed))
+
+		_, ok := bindings.Get(credentials)
+		Expect(ok).To(BeFalse())
+	})
+})
+
+func newHANAAdminCredentials(name string) *hanav1.HANAAdminCredentials {
+	return &hanav1.HANAAdminCredentials{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+		},
+		Spec: hanav1.HANAAdminCredentialsSpec{
+			SecretRef: &hanav1.LocalObjectReference{Name: adminAPIAccessSecret},
+			ProxyURL:  "http://proxy:3128",
+		},
+	}
+}
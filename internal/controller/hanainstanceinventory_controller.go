@@ -0,0 +1,206 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	defaultInventoryRefreshInterval = 5 * time.Minute
+)
+
+// HANAInstanceInventoryReconciler reconciles a HANAInstanceInventory object
+type HANAInstanceInventoryReconciler struct {
+	Client             client.Client
+	Log                logr.Logger
+	Scheme             *runtime.Scheme
+	GetInventoryClient func(adminAPIAccessBinding inventory.Binding) inventory.Client
+	// Bindings holds the bindings of validated HANAAdminCredentials.
+	Bindings *BindingStore
+
+	// DefaultRefreshInterval applies to inventories without refreshInterval, it defaults to 5m.
+	DefaultRefreshInterval  time.Duration
+	MaxConcurrentReconciles int
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HANAInstanceInventoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hanav1.HANAInstanceInventory{}).
+		WithEventFilter(predicate.GenerationChangedPredicate{}).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanainstanceinventories,verbs=get;list;watch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanainstanceinventories/status,verbs=get;update;patch
+
+// Reconcile lists all mappings of the service instance of a
+// HANAInstanceInventory and records them in its status. The inventory is
+// refreshed periodically as mappings may be changed outside of the cluster.
+func (r *HANAInstanceInventoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("hanainstanceinventory", req.NamespacedName).WithValues("correlation_id", uuid.New().String())
+
+	instanceInventory := &hanav1.HANAInstanceInventory{}
+	if err := r.Client.Get(ctx, req.NamespacedName, instanceInventory); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	log.Info(fmt.Sprintf("got hanainstanceinventory gen %d", instanceInventory.Generation))
+	instanceInventory = instanceInventory.DeepCopy()
+
+	refreshInterval := r.DefaultRefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = defaultInventoryRefreshInterval
+	}
+	if instanceInventory.Spec.RefreshInterval != nil && instanceInventory.Spec.RefreshInterval.Duration > 0 {
+		refreshInterval = instanceInventory.Spec.RefreshInterval.Duration
+	}
+
+	mappings, err := r.listMappings(ctx, instanceInventory)
+	if err != nil {
+		if statusErr := r.setStatus(ctx, instanceInventory, metav1.ConditionFalse, failureReason(err), err.Error()); statusErr != nil {
+			log.Error(statusErr, "failed to record sync error")
+		}
+		if err == inventory.ErrCircuitOpen {
+			return ctrl.Result{RequeueAfter: inventoryUnavailableRequeueInterval}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	log.Info(fmt.Sprintf("listed %d mappings", len(mappings)))
+
+	now := metav1.Now()
+	instanceInventory.Status.LastSyncTime = &now
+	instanceInventory.Status.Mappings = mappings
+	if statusErr := r.setStatus(ctx, instanceInventory, metav1.ConditionTrue, conditionReasonSucceeded, ""); statusErr != nil {
+		return ctrl.Result{}, statusErr
+	}
+
+	return ctrl.Result{RequeueAfter: refreshInterval}, nil
+}
+
+func (r *HANAInstanceInventoryReconciler) listMappings(ctx context.Context, instanceInventory *hanav1.HANAInstanceInventory) ([]hanav1.InventoryMapping, error) {
+	adminAPIAccessBinding, err := resolveAdminAPIAccessBinding(ctx, r.Client, r.Bindings, instanceInventory.Namespace,
+		instanceInventory.Spec.AdminAPIAccessSecret, instanceInventory.Spec.AdminCredentials)
+	if err != nil {
+		return nil, err
+	}
+
+	inventoryClient := r.GetInventoryClient(adminAPIAccessBinding)
+	inventoryMappings, err := inventoryClient.ListMappings(ctx, instanceInventory.Spec.ServiceInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	owners, err := getMappingOwners(ctx, r.Client, instanceInventory.Spec.ServiceInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make([]hanav1.InventoryMapping, 0, len(inventoryMappings))
+	for _, inventoryMapping := range inventoryMappings {
+		owner, owned := owners[inventoryMappingID(instanceInventory.Spec.ServiceInstanceID, inventoryMapping)]
+		mappings = append(mappings, hanav1.InventoryMapping{
+			Platform:    inventoryMapping.Platform,
+			PrimaryID:   inventoryMapping.PrimaryID,
+			SecondaryID: inventoryMapping.SecondaryID,
+			IsDefault:   inventoryMapping.IsDefault,
+			Owned:       owned,
+			OwnedBy:     owner,
+		})
+	}
+	return mappings, nil
+}
+
+// inventoryMappingID returns the normalized mapping ID of an inventory mapping
+// of the service instance, as used as key of the mapping owners.
+func inventoryMappingID(serviceInstanceID string, mapping inventory.Mapping) hanav1.MappingID {
+	return hanav1.MappingID{
+		Platform:          mappingPlatform(mapping.Platform),
+		ServiceInstanceID: serviceInstanceID,
+		PrimaryID:         mapping.PrimaryID,
+		SecondaryID:       mapping.SecondaryID,
+	}
+}
+
+// getMappingOwners returns the HANAMappings and ClusterHANAMappings of this
+// cluster by the mapping IDs of the service instance they own.
+func getMappingOwners(ctx context.Context, c client.Reader, serviceInstanceID string) (map[hanav1.MappingID]string, error) {
+	owners := map[hanav1.MappingID]string{}
+
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := c.List(ctx, hanaMappings); err != nil {
+		return nil, err
+	}
+	for _, hanaMapping := range hanaMappings.Items {
+		mappingID := normalizeMappingID(hanaMapping.Status.MappingID)
+		if mappingID == nil || mappingID.ServiceInstanceID != serviceInstanceID {
+			continue
+		}
+		owners[*mappingID] = hanaMapping.Namespace + "/" + hanaMapping.Name
+	}
+
+	clusterMappings := &hanav1.ClusterHANAMappingList{}
+	if err := c.List(ctx, clusterMappings); err != nil {
+		return nil, err
+	}
+	for _, clusterMapping := range clusterMappings.Items {
+		for i := range clusterMapping.Status.MappingIDs {
+			mappingID := normalizeMappingID(&clusterMapping.Status.MappingIDs[i])
+			if mappingID.ServiceInstanceID != serviceInstanceID {
+				continue
+			}
+			owners[*mappingID] = clusterMapping.Name
+		}
+	}
+
+	return owners, nil
+}
+
+func (r *HANAInstanceInventoryReconciler) setStatus(ctx context.Context, instanceInventory *hanav1.HANAInstanceInventory, status metav1.ConditionStatus, reason, message string) error {
+	condition := metav1.Condition{
+		Type:    conditionTypeReady,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	}
+	lastSyncTime, mappings := instanceInventory.Status.LastSyncTime, instanceInventory.Status.Mappings
+	return patchStatus(ctx, r.Client, instanceInventory, func() {
+		meta.SetStatusCondition(&instanceInventory.Status.Conditions, condition)
+		instanceInventory.Status.LastSyncTime = lastSyncTime
+		instanceInventory.Status.Mappings = mappings
+	})
+}
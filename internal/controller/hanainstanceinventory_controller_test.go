@@ -0,0 +1,121 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	instanceInventoryName = "test-instanceinventory"
+)
+
+var _ = Describe("HANAInstanceInventory Controller", func() {
+	var (
+		log logr.Logger
+		ctx context.Context
+	)
+
+	BeforeEach(func() {
+		log = ctrl.Log.WithName("test-log")
+		ctx = context.Background()
+
+		hanamapping := newHANAMapping(hanamappingName)
+		Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+		hanamapping.Status.Conditions = []metav1.Condition{{
+			LastTransitionTime: metav1.Now(),
+			Type:               conditionTypeReady,
+			Status:             metav1.ConditionTrue,
+			Reason:             conditionReasonSucceeded,
+		}}
+		hanamapping.Status.MappingID = &hanav1.MappingID{
+			ServiceInstanceID: hanamappingServiceInstanceID,
+			PrimaryID:         clusterID,
+			SecondaryID:       hanamappingTargetNamespace,
+		}
+		Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+		Expect(k8sClient.Create(ctx, &hanav1.HANAInstanceInventory{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: testNamespace,
+				Name:      instanceInventoryName,
+			},
+			Spec: hanav1.HANAInstanceInventorySpec{
+				AdminAPIAccessSecret: &hanav1.NamespacedName{
+					Namespace: testNamespace,
+					Name:      adminAPIAccessSecret,
+				},
+				ServiceInstanceID: hanamappingServiceInstanceID,
+			},
+		})).To(Succeed())
+	})
+
+	AfterEach(func() {
+		hanamapping := &hanav1.HANAMapping{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+
+		instanceInventory := &hanav1.HANAInstanceInventory{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: instanceInventoryName}, instanceInventory)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, instanceInventory)).To(Succeed())
+	})
+
+	It("should list all mappings of the instance and flag the owned ones", func() {
+		inventoryClientStub := &inventoryClientStub{}
+		inventoryClientStub.ListMappingsReturns([]inventory.Mapping{
+			newInventoryMapping(),
+			{Platform: hanav1.PlatformCloudFoundry, PrimaryID: "test-org", SecondaryID: "test-space"},
+		}, nil)
+
+		controllerReconciler := &HANAInstanceInventoryReconciler{
+			Client:             k8sClient,
+			Log:                log,
+			Scheme:             k8sClient.Scheme(),
+			GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+		}
+
+		result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+			NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: instanceInventoryName},
+		})
+
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(defaultInventoryRefreshInterval))
+
+		instanceInventory := &hanav1.HANAInstanceInventory{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: instanceInventoryName}, instanceInventory)).To(Succeed())
+		Expect(instanceInventory.Status.LastSyncTime).NotTo(BeNil())
+		Expect(instanceInventory.Status.Mappings).To(HaveLen(2))
+		Expect(instanceInventory.Status.Mappings[0].Owned).To(BeTrue())
+		Expect(instanceInventory.Status.Mappings[0].OwnedBy).To(Equal(testNamespace + "/" + hanamappingName))
+		Expect(instanceInventory.Status.Mappings[1].Owned).To(BeFalse())
+	})
+})
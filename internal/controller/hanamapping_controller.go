@@ -18,8 +18,8 @@ package controller
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/google/uuid"
@@ -28,27 +28,31 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
 	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
 )
 
 const (
-	defaultBTPOperatorConfigmapNamespace = "kyma-system"
-	defaultBTPOperatorConfigmapName      = "sap-btp-operator-config"
-
 	finalizerName = "hanamappings.hana.cloud.sap.com/finalizer"
 
-	conditionTypeReady = "Ready"
+	conditionTypeRemapping = "Remapping"
+
+	conditionReasonDefaultConflict = "DefaultConflict"
+	conditionReasonInvalidMapping  = "InvalidMapping"
 
-	conditionReasonInProgress = "InProgress"
-	conditionReasonSucceeded  = "Succeeded"
-	conditionReasonFailed     = "Failed"
+	eventReasonMappingOrphaned = "MappingOrphaned"
 )
 
 // HANAMappingReconciler reconciles a HANAMapping object
@@ -56,22 +60,49 @@ type HANAMappingReconciler struct {
 	Client             client.Client
 	Log                logr.Logger
 	Scheme             *runtime.Scheme
+	Recorder           record.EventRecorder
 	GetInventoryClient func(adminAPIAccessBinding inventory.Binding) inventory.Client
+	// Bindings holds the bindings of validated HANAAdminCredentials.
+	Bindings *BindingStore
+
+	ClusterIDOptions ClusterIDOptions
+	// DefaultDeletionPolicy applies to HANAMappings without deletionPolicy, it defaults to Delete.
+	DefaultDeletionPolicy string
+	// ResyncInterval is how often synced HANAMappings are compared with the
+	// inventory again, so that drift is corrected. It is disabled if not positive.
+	ResyncInterval time.Duration
+	// ClusterIDMigrations receives the HANAMappings the ClusterIDMigrator
+	// started to migrate. If it is set, HANAMappings whose resolved cluster ID
+	// changed keep their mapping until their migration is started.
+	ClusterIDMigrations     <-chan event.GenericEvent
+	MaxConcurrentReconciles int
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *HANAMappingReconciler) SetupWithManager(mgr ctrl.Manager) error {
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&hanav1.HANAMapping{}).
-		WithEventFilter(predicate.GenerationChangedPredicate{}).
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&hanav1.HANAMapping{}, builder.WithPredicates(predicate.Or(predicate.GenerationChangedPredicate{},
+			annotationChangedPredicate(hanav1.ResyncAnnotation, hanav1.AdoptAnnotation)))).
+		Watches(&hanav1.HANAMappingCredentialGrant{}, handler.EnqueueRequestsFromMapFunc(r.hanaMappingsForCredentialGrant),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&hanav1.HANAAdminCredentials{}, handler.EnqueueRequestsFromMapFunc(r.hanaMappingsForAdminCredentials)).
+		Watches(&hanav1.HANAMapping{}, handler.EnqueueRequestsFromMapFunc(r.hanaMappingsForReleasedDefault),
+			builder.WithPredicates(defaultReleasedPredicate()))
+	if r.ClusterIDMigrations != nil {
+		b = b.WatchesRawSource(&source.Channel{Source: r.ClusterIDMigrations}, &handler.EnqueueRequestForObject{})
+	}
+	return b.WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
 		Complete(r)
 }
 
 //+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
 //+kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappings,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappings/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappings/finalizers,verbs=update
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingcredentialgrants,verbs=get;list;watch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanaadmincredentials,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -98,15 +129,13 @@ func (r *HANAMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	if !hanaMapping.ObjectMeta.DeletionTimestamp.IsZero() {
 		if controllerutil.ContainsFinalizer(hanaMapping, finalizerName) {
 			if err := r.deleteMapping(ctx, hanaMapping); err != nil {
-				if statusErr := r.setStatusFailed(ctx, hanaMapping, err); statusErr != nil {
-					return ctrl.Result{}, statusErr
-				}
-				return ctrl.Result{}, err
+				return r.handleSyncError(ctx, hanaMapping, err)
 			}
 			log.Info("deleted mapping")
 
-			controllerutil.RemoveFinalizer(hanaMapping, finalizerName)
-			if err := r.Client.Update(ctx, hanaMapping); err != nil {
+			if err := patchObject(ctx, r.Client, hanaMapping, func() {
+				controllerutil.RemoveFinalizer(hanaMapping, finalizerName)
+			}); err != nil {
 				return ctrl.Result{}, err
 			}
 			log.Info("removed finalizer")
@@ -116,8 +145,9 @@ func (r *HANAMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	}
 
 	if !controllerutil.ContainsFinalizer(hanaMapping, finalizerName) {
-		controllerutil.AddFinalizer(hanaMapping, finalizerName)
-		if err := r.Client.Update(ctx, hanaMapping); err != nil {
+		if err := patchObject(ctx, r.Client, hanaMapping, func() {
+			controllerutil.AddFinalizer(hanaMapping, finalizerName)
+		}); err != nil {
 			return ctrl.Result{}, err
 		}
 		log.Info("added finalizer")
@@ -131,9 +161,10 @@ func (r *HANAMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		log.Info("initialized status")
 	}
 
-	if len(hanaMapping.Spec.Mapping.TargetNamespace) == 0 {
-		hanaMapping.Spec.Mapping.TargetNamespace = hanaMapping.Namespace
-		if err := r.Client.Update(ctx, hanaMapping); err != nil {
+	if mappingPlatform(hanaMapping.Spec.Mapping.Platform) == hanav1.PlatformKubernetes && len(hanaMapping.Spec.Mapping.TargetNamespace) == 0 {
+		if err := patchObject(ctx, r.Client, hanaMapping, func() {
+			hanaMapping.Spec.Mapping.TargetNamespace = hanaMapping.Namespace
+		}); err != nil {
 			return ctrl.Result{}, err
 		}
 		log.Info("set default targetNamespace")
@@ -142,10 +173,7 @@ func (r *HANAMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	newMappingID, err := r.syncMapping(ctx, hanaMapping)
 	if err != nil {
-		if statusErr := r.setStatusFailed(ctx, hanaMapping, err); statusErr != nil {
-			return ctrl.Result{}, statusErr
-		}
-		return ctrl.Result{}, err
+		return r.handleSyncError(ctx, hanaMapping, err)
 	}
 	log.Info("synced mapping")
 
@@ -153,24 +181,51 @@ func (r *HANAMappingReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, statusErr
 	}
 
-	return ctrl.Result{}, nil
+	if _, ok := hanaMapping.Annotations[hanav1.AdoptAnnotation]; ok {
+		if err := patchObject(ctx, r.Client, hanaMapping, func() {
+			delete(hanaMapping.Annotations, hanav1.AdoptAnnotation)
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info("adopted mapping")
+	}
+
+	return ctrl.Result{RequeueAfter: r.ResyncInterval}, nil
+}
+
+// handleSyncError records a failed sync in the status. While the circuit
+// breaker of the inventory API is open the mapping is requeued after a fixed
+// interval instead of being retried with backoff. A failure to record the
+// status is only logged, so that it does not mask the sync error.
+func (r *HANAMappingReconciler) handleSyncError(ctx context.Context, hanaMapping *hanav1.HANAMapping, err error) (ctrl.Result, error) {
+	if statusErr := r.setStatusFailed(ctx, hanaMapping, failureReason(err), err); statusErr != nil {
+		r.Log.Error(statusErr, "failed to record sync error", "hanamapping", client.ObjectKeyFromObject(hanaMapping))
+	}
+	if err == inventory.ErrCircuitOpen {
+		return ctrl.Result{RequeueAfter: inventoryUnavailableRequeueInterval}, nil
+	}
+	return ctrl.Result{}, err
 }
 
 func (r *HANAMappingReconciler) syncMapping(ctx context.Context, hanaMapping *hanav1.HANAMapping) (*hanav1.MappingID, error) {
-	clusterID, err := r.getClusterID(ctx, hanaMapping)
+	newMappingID, err := r.getDesiredMappingID(ctx, hanaMapping)
 	if err != nil {
 		return nil, err
 	}
 
-	oldMappingID := hanaMapping.Status.MappingID
-	newMappingID := &hanav1.MappingID{
-		ServiceInstanceID: hanaMapping.Spec.Mapping.ServiceInstanceID,
-		PrimaryID:         clusterID,
-		SecondaryID:       hanaMapping.Spec.Mapping.TargetNamespace,
+	oldMappingID := normalizeMappingID(hanaMapping.Status.MappingID)
+	if r.ClusterIDMigrations != nil && clusterIDMigrationPending(hanaMapping, oldMappingID, newMappingID) {
+		newMappingID = oldMappingID
 	}
 	deleteOldMapping := (oldMappingID != nil) && (*newMappingID != *oldMappingID)
 	overwriteNewMapping := (oldMappingID != nil) && (*newMappingID == *oldMappingID)
 
+	if hanaMapping.Spec.Mapping.IsDefault {
+		if err := r.checkDefaultConflict(ctx, hanaMapping); err != nil {
+			return nil, err
+		}
+	}
+
 	adminAPIAccessBinding, err := r.getAdminAPIAccessBinding(ctx, hanaMapping)
 	if err != nil {
 		return nil, err
@@ -178,46 +233,210 @@ func (r *HANAMappingReconciler) syncMapping(ctx context.Context, hanaMapping *ha
 
 	inventoryClient := r.GetInventoryClient(adminAPIAccessBinding)
 
+	if err := r.replayOperations(ctx, hanaMapping, inventoryClient, newMappingID); err != nil {
+		return nil, err
+	}
+
 	if deleteOldMapping {
-		inventoryErr := inventoryClient.DeleteMapping(ctx, oldMappingID.ServiceInstanceID, oldMappingID.PrimaryID, oldMappingID.SecondaryID)
-		if inventoryErr != nil {
-			if inventoryErr != inventory.ErrMappingNotFound {
-				return nil, inventoryErr
-			}
+		if err := r.remapMapping(ctx, hanaMapping, inventoryClient, oldMappingID, newMappingID); err != nil {
+			return nil, err
 		}
+		return newMappingID, nil
 	}
 
-	mapping := inventory.Mapping{
-		Platform:    "kubernetes",
-		PrimaryID:   newMappingID.PrimaryID,
-		SecondaryID: newMappingID.SecondaryID,
+	if !overwriteNewMapping && !hasOperation(hanaMapping.Status.Operations, hanav1.InventoryOperationCreate, newMappingID) {
+		if err := r.ensureMappingNotExists(ctx, hanaMapping, inventoryClient, newMappingID); err != nil {
+			return nil, err
+		}
+		if err := r.recordOperation(ctx, hanaMapping, hanav1.InventoryOperationCreate, newMappingID); err != nil {
+			return nil, err
+		}
 	}
 
-	inventoryErr := inventoryClient.CreateMapping(ctx, newMappingID.ServiceInstanceID, mapping)
-	if inventoryErr != nil {
-		if !overwriteNewMapping || (overwriteNewMapping && (inventoryErr != inventory.ErrMappingAlreadyExists)) {
-			return nil, inventoryErr
+	if err := createInventoryMapping(ctx, inventoryClient, newMappingID, hanaMapping.Spec.Mapping.IsDefault, true); err != nil {
+		if overwriteNewMapping && inventory.IsMappingRemoved(err) {
+			if statusErr := r.setStatusMappingRemoved(ctx, hanaMapping, newMappingID); statusErr != nil {
+				r.Log.Error(statusErr, "failed to record removed mapping", "hanamapping", client.ObjectKeyFromObject(hanaMapping))
+			}
 		}
+		return nil, err
 	}
 
 	return newMappingID, nil
 }
 
+// clusterIDMigrationPending reports whether the HANAMapping only has to be
+// remapped because its resolved cluster ID changed, and the ClusterIDMigrator
+// has not started its migration yet. Such remaps are left to the migrator, so
+// that it bounds how many HANAMappings are migrated at a time.
+func clusterIDMigrationPending(hanaMapping *hanav1.HANAMapping, oldMappingID, newMappingID *hanav1.MappingID) bool {
+	if oldMappingID == nil || oldMappingID.PrimaryID == newMappingID.PrimaryID || len(hanaMapping.Spec.ClusterID) > 0 ||
+		meta.IsStatusConditionTrue(hanaMapping.Status.Conditions, conditionTypeClusterIDMigration) {
+		return false
+	}
+	migratedMappingID := *oldMappingID
+	migratedMappingID.PrimaryID = newMappingID.PrimaryID
+	return newMappingID.Platform == hanav1.PlatformKubernetes && migratedMappingID == *newMappingID
+}
+
+// replayOperations completes the operations journaled by an earlier
+// reconciliation whose outcome never made it into the status. Mappings created
+// for a target which is neither desired nor recorded anymore are deleted and
+// dropped from the journal, the desired and the recorded mapping are left to
+// the regular sync.
+func (r *HANAMappingReconciler) replayOperations(ctx context.Context, hanaMapping *hanav1.HANAMapping, inventoryClient inventory.Client, desiredMappingID *hanav1.MappingID) error {
+	currentMappingID := normalizeMappingID(hanaMapping.Status.MappingID)
+
+	return replayOperations(ctx, r.Client, hanaMapping, &hanaMapping.Status.Operations, inventoryClient,
+		func(mappingID *hanav1.MappingID) bool {
+			return *mappingID == *desiredMappingID || (currentMappingID != nil && *mappingID == *currentMappingID)
+		},
+		func(mappingID *hanav1.MappingID) {
+			if pendingMappingID := normalizeMappingID(hanaMapping.Status.PendingMappingID); pendingMappingID != nil && *pendingMappingID == *mappingID {
+				hanaMapping.Status.PendingMappingID = nil
+			}
+		})
+}
+
+// recordOperation journals an inventory operation in the status before it is
+// performed.
+func (r *HANAMappingReconciler) recordOperation(ctx context.Context, hanaMapping *hanav1.HANAMapping, operationType string, mappingID *hanav1.MappingID) error {
+	if hasOperation(hanaMapping.Status.Operations, operationType, mappingID) {
+		return nil
+	}
+	return patchStatus(ctx, r.Client, hanaMapping, func() {
+		if !hasOperation(hanaMapping.Status.Operations, operationType, mappingID) {
+			hanaMapping.Status.Operations = append(hanaMapping.Status.Operations, newInventoryOperation(operationType, mappingID))
+		}
+	})
+}
+
+func newInventoryOperation(operationType string, mappingID *hanav1.MappingID) hanav1.InventoryOperation {
+	return hanav1.InventoryOperation{
+		Type:      operationType,
+		MappingID: *mappingID,
+		StartedAt: metav1.Now(),
+	}
+}
+
+func hasOperation(operations []hanav1.InventoryOperation, operationType string, mappingID *hanav1.MappingID) bool {
+	for _, operation := range operations {
+		if operation.Type == operationType && *normalizeMappingID(&operation.MappingID) == *mappingID {
+			return true
+		}
+	}
+	return false
+}
+
+// ensureMappingNotExists fails if a mapping which is about to be created
+// exists already, so that it is not journaled and taken over, unless the
+// HANAMapping is annotated to adopt it.
+func (r *HANAMappingReconciler) ensureMappingNotExists(ctx context.Context, hanaMapping *hanav1.HANAMapping, inventoryClient inventory.Client, mappingID *hanav1.MappingID) error {
+	if hanaMapping.Annotations[hanav1.AdoptAnnotation] == "true" {
+		return nil
+	}
+
+	return ensureInventoryMappingNotExists(ctx, inventoryClient, mappingID)
+}
+
+// remapMapping replaces the old mapping by the new one. The new mapping is
+// created and confirmed before the old one is deleted, so the workload is
+// never left without a mapping. The new mapping is recorded as pending in the
+// status before it is created, so an interrupted remap is resumed instead of
+// leaking it.
+func (r *HANAMappingReconciler) remapMapping(ctx context.Context, hanaMapping *hanav1.HANAMapping, inventoryClient inventory.Client, oldMappingID, newMappingID *hanav1.MappingID) error {
+	pendingMappingID := normalizeMappingID(hanaMapping.Status.PendingMappingID)
+	if pendingMappingID != nil && *pendingMappingID != *newMappingID {
+		if err := deleteInventoryMapping(ctx, inventoryClient, pendingMappingID); err != nil {
+			return err
+		}
+		pendingMappingID = nil
+	}
+
+	if pendingMappingID == nil {
+		if err := r.ensureMappingNotExists(ctx, hanaMapping, inventoryClient, newMappingID); err != nil {
+			return err
+		}
+
+		if err := r.setStatusRemapping(ctx, hanaMapping, oldMappingID, newMappingID); err != nil {
+			return err
+		}
+	}
+
+	if err := createInventoryMapping(ctx, inventoryClient, newMappingID, hanaMapping.Spec.Mapping.IsDefault, true); err != nil {
+		return err
+	}
+
+	mappings, err := inventoryClient.ListMappings(ctx, newMappingID.ServiceInstanceID)
+	if err != nil {
+		return err
+	}
+	if !containsMapping(mappings, newMappingID) {
+		return fmt.Errorf("mapping %s/%s of service instance %s is not listed after its creation", newMappingID.PrimaryID, newMappingID.SecondaryID, newMappingID.ServiceInstanceID)
+	}
+
+	if err := r.recordOperation(ctx, hanaMapping, hanav1.InventoryOperationDelete, oldMappingID); err != nil {
+		return err
+	}
+
+	return deleteInventoryMapping(ctx, inventoryClient, oldMappingID)
+}
+
+func containsMappingID(mappingIDs []*hanav1.MappingID, mappingID *hanav1.MappingID) bool {
+	for _, id := range mappingIDs {
+		if *id == *mappingID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMapping(mappings []inventory.Mapping, mappingID *hanav1.MappingID) bool {
+	for _, mapping := range mappings {
+		if mapping.PrimaryID == mappingID.PrimaryID && mapping.SecondaryID == mappingID.SecondaryID {
+			return true
+		}
+	}
+	return false
+}
+
 func (r *HANAMappingReconciler) deleteMapping(ctx context.Context, hanaMapping *hanav1.HANAMapping) error {
-	mappingID := hanaMapping.Status.MappingID
+	var mappingIDs []*hanav1.MappingID
+	for _, mappingID := range []*hanav1.MappingID{hanaMapping.Status.MappingID, hanaMapping.Status.PendingMappingID} {
+		if mappingID != nil {
+			mappingIDs = append(mappingIDs, normalizeMappingID(mappingID))
+		}
+	}
+	for i := range hanaMapping.Status.Operations {
+		operation := &hanaMapping.Status.Operations[i]
+		if operation.Type == hanav1.InventoryOperationCreate && !containsMappingID(mappingIDs, normalizeMappingID(&operation.MappingID)) {
+			mappingIDs = append(mappingIDs, normalizeMappingID(&operation.MappingID))
+		}
+	}
 
-	if mappingID != nil {
+	deletionPolicy := hanaMapping.Spec.DeletionPolicy
+	if len(deletionPolicy) == 0 {
+		deletionPolicy = r.DefaultDeletionPolicy
+	}
+	if len(mappingIDs) > 0 && deletionPolicy != hanav1.DeletionPolicyOrphan {
 		adminAPIAccessBinding, err := r.getAdminAPIAccessBinding(ctx, hanaMapping)
+		if failureReason(err) == conditionReasonCredentialsNotPermitted {
+			// The grant was revoked after the mapping was created. The secret
+			// must not be used anymore, so the mappings are orphaned rather
+			// than blocking the deletion for good.
+			r.event(hanaMapping, corev1.EventTypeWarning, eventReasonMappingOrphaned,
+				fmt.Sprintf("left %d mappings in the inventory: %s", len(mappingIDs), err.Error()))
+			return nil
+		}
 		if err != nil {
 			return err
 		}
 
 		inventoryClient := r.GetInventoryClient(adminAPIAccessBinding)
 
-		inventoryErr := inventoryClient.DeleteMapping(ctx, mappingID.ServiceInstanceID, mappingID.PrimaryID, mappingID.SecondaryID)
-		if inventoryErr != nil {
-			if inventoryErr != inventory.ErrMappingNotFound {
-				return inventoryErr
+		for _, mappingID := range mappingIDs {
+			if err := deleteInventoryMapping(ctx, inventoryClient, mappingID); err != nil {
+				return err
 			}
 		}
 	}
@@ -225,76 +444,276 @@ func (r *HANAMappingReconciler) deleteMapping(ctx context.Context, hanaMapping *
 	return nil
 }
 
-func (r *HANAMappingReconciler) getClusterID(ctx context.Context, hanaMapping *hanav1.HANAMapping) (string, error) {
-	cmNamespace := hanaMapping.Spec.BTPOperatorConfigmap.Namespace
-	if len(cmNamespace) == 0 {
-		cmNamespace = defaultBTPOperatorConfigmapNamespace
+func (r *HANAMappingReconciler) event(hanaMapping *hanav1.HANAMapping, eventType, reason, message string) {
+	r.Log.Info(message, "hanamapping", client.ObjectKeyFromObject(hanaMapping), "reason", reason)
+	if r.Recorder == nil {
+		return
 	}
+	r.Recorder.Event(hanaMapping, eventType, reason, message)
+}
+
+// getDesiredMappingID returns the mapping ID the HANAMapping should result in.
+// On kubernetes it is derived from the cluster ID and the target namespace, on
+// cloudfoundry the IDs are given explicitly.
+func (r *HANAMappingReconciler) getDesiredMappingID(ctx context.Context, hanaMapping *hanav1.HANAMapping) (*hanav1.MappingID, error) {
+	mapping := hanaMapping.Spec.Mapping
+
+	switch platform := mappingPlatform(mapping.Platform); platform {
+	case hanav1.PlatformKubernetes:
+		clusterID, err := r.getClusterID(ctx, hanaMapping)
+		if err != nil {
+			return nil, err
+		}
 
-	cmName := hanaMapping.Spec.BTPOperatorConfigmap.Name
-	if len(cmName) == 0 {
-		cmName = defaultBTPOperatorConfigmapName
+		return &hanav1.MappingID{
+			Platform:          platform,
+			ServiceInstanceID: mapping.ServiceInstanceID,
+			PrimaryID:         clusterID,
+			SecondaryID:       mapping.TargetNamespace,
+		}, nil
+	case hanav1.PlatformCloudFoundry:
+		if len(mapping.PrimaryID) == 0 || len(mapping.SecondaryID) == 0 {
+			return nil, &conditionError{
+				reason:  conditionReasonInvalidMapping,
+				message: "primaryID and secondaryID are required on platform " + platform,
+			}
+		}
+
+		return &hanav1.MappingID{
+			Platform:          platform,
+			ServiceInstanceID: mapping.ServiceInstanceID,
+			PrimaryID:         mapping.PrimaryID,
+			SecondaryID:       mapping.SecondaryID,
+		}, nil
+	default:
+		return nil, &conditionError{
+			reason:  conditionReasonInvalidMapping,
+			message: "unsupported platform " + platform,
+		}
 	}
+}
 
-	cm := &corev1.ConfigMap{}
-	err := r.Client.Get(ctx, types.NamespacedName{Namespace: cmNamespace, Name: cmName}, cm)
-	if err != nil {
-		return "", err
+// defaultScope identifies the target a default instance is chosen for.
+func defaultScope(mapping hanav1.Mapping) string {
+	if platform := mappingPlatform(mapping.Platform); platform != hanav1.PlatformKubernetes {
+		return platform + "/" + mapping.PrimaryID + "/" + mapping.SecondaryID
+	}
+	return hanav1.PlatformKubernetes + "/" + mapping.TargetNamespace
+}
+
+// checkDefaultConflict makes sure that no other HANAMapping marks an instance
+// as default for the same target. The oldest HANAMapping wins.
+func (r *HANAMappingReconciler) checkDefaultConflict(ctx context.Context, hanaMapping *hanav1.HANAMapping) error {
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := r.Client.List(ctx, hanaMappings); err != nil {
+		return err
 	}
 
-	clusterID := cm.Data["CLUSTER_ID"]
-	return clusterID, nil
+	for i := range hanaMappings.Items {
+		other := &hanaMappings.Items[i]
+		if other.UID == hanaMapping.UID || !other.Spec.Mapping.IsDefault || !other.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if defaultScope(other.Spec.Mapping) != defaultScope(hanaMapping.Spec.Mapping) {
+			continue
+		}
+		if isOlder(other, hanaMapping) {
+			return &conditionError{
+				reason:  conditionReasonDefaultConflict,
+				message: fmt.Sprintf("hanamapping %s/%s already marks a default instance for %s", other.Namespace, other.Name, defaultScope(hanaMapping.Spec.Mapping)),
+			}
+		}
+	}
+
+	return nil
+}
+
+func isOlder(a, b client.Object) bool {
+	aCreated, bCreated := a.GetCreationTimestamp().Time, b.GetCreationTimestamp().Time
+	if !aCreated.Equal(bCreated) {
+		return aCreated.Before(bCreated)
+	}
+	return a.GetNamespace()+"/"+a.GetName() < b.GetNamespace()+"/"+b.GetName()
+}
+
+func (r *HANAMappingReconciler) getClusterID(ctx context.Context, hanaMapping *hanav1.HANAMapping) (string, error) {
+	return getClusterID(ctx, r.Client, r.ClusterIDOptions, hanaMapping.Spec.ClusterID, hanaMapping.Spec.BTPOperatorConfigmap)
 }
 
 func (r *HANAMappingReconciler) getAdminAPIAccessBinding(ctx context.Context, hanaMapping *hanav1.HANAMapping) (inventory.Binding, error) {
-	secret := &corev1.Secret{}
-	err := r.Client.Get(ctx, types.NamespacedName{Namespace: hanaMapping.Spec.AdminAPIAccessSecret.Namespace, Name: hanaMapping.Spec.AdminAPIAccessSecret.Name}, secret)
-	if err != nil {
-		return inventory.Binding{}, err
+	return resolveAdminAPIAccessBinding(ctx, r.Client, r.Bindings, hanaMapping.Namespace, hanaMapping.Spec.AdminAPIAccessSecret, hanaMapping.Spec.AdminCredentials)
+}
+
+// hanaMappingsForCredentialGrant enqueues the HANAMappings which reference a
+// secret in the namespace of a changed HANAMappingCredentialGrant.
+func (r *HANAMappingReconciler) hanaMappingsForCredentialGrant(ctx context.Context, obj client.Object) []reconcile.Request {
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := r.Client.List(ctx, hanaMappings); err != nil {
+		r.Log.Error(err, "failed to list hanamappings for credential grant", "hanamappingcredentialgrant", client.ObjectKeyFromObject(obj))
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, hanaMapping := range hanaMappings.Items {
+		secret := hanaMapping.Spec.AdminAPIAccessSecret
+		if secret == nil || secret.Namespace != obj.GetNamespace() || hanaMapping.Namespace == obj.GetNamespace() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&hanaMapping)})
+	}
+	return requests
+}
+
+// defaultReleasedPredicate passes HANAMappings which stop marking a default
+// instance for their target, because they are deleted or changed.
+func defaultReleasedPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool {
+			return false
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldMapping, ok := e.ObjectOld.(*hanav1.HANAMapping)
+			if !ok || !oldMapping.Spec.Mapping.IsDefault {
+				return false
+			}
+			newMapping, ok := e.ObjectNew.(*hanav1.HANAMapping)
+			return ok && (!newMapping.Spec.Mapping.IsDefault || !newMapping.DeletionTimestamp.IsZero() ||
+				defaultScope(oldMapping.Spec.Mapping) != defaultScope(newMapping.Spec.Mapping))
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			hanaMapping, ok := e.Object.(*hanav1.HANAMapping)
+			return ok && hanaMapping.Spec.Mapping.IsDefault
+		},
+		GenericFunc: func(event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// hanaMappingsForReleasedDefault enqueues the HANAMappings which mark a
+// default instance for the same target as a HANAMapping which stopped doing
+// so, as one of them may have been blocked by a default conflict.
+func (r *HANAMappingReconciler) hanaMappingsForReleasedDefault(ctx context.Context, obj client.Object) []reconcile.Request {
+	released, ok := obj.(*hanav1.HANAMapping)
+	if !ok {
+		return nil
 	}
 
-	baseURL := string(secret.Data["baseurl"])
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := r.Client.List(ctx, hanaMappings); err != nil {
+		r.Log.Error(err, "failed to list hanamappings for released default", "hanamapping", client.ObjectKeyFromObject(obj))
+		return nil
+	}
 
-	uaa := inventory.BindingUAA{}
-	if err = json.Unmarshal(secret.Data["uaa"], &uaa); err != nil {
-		return inventory.Binding{}, err
+	var requests []reconcile.Request
+	for _, hanaMapping := range hanaMappings.Items {
+		if hanaMapping.UID == released.UID || !hanaMapping.Spec.Mapping.IsDefault ||
+			defaultScope(hanaMapping.Spec.Mapping) != defaultScope(released.Spec.Mapping) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&hanaMapping)})
 	}
+	return requests
+}
 
-	binding := inventory.Binding{
-		BaseURL: baseURL,
-		UAA:     uaa,
+// hanaMappingsForAdminCredentials enqueues the HANAMappings which reference a
+// changed HANAAdminCredentials.
+func (r *HANAMappingReconciler) hanaMappingsForAdminCredentials(ctx context.Context, obj client.Object) []reconcile.Request {
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := r.Client.List(ctx, hanaMappings, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "failed to list hanamappings for admin credentials", "hanaadmincredentials", client.ObjectKeyFromObject(obj))
+		return nil
 	}
-	return binding, nil
+
+	var requests []reconcile.Request
+	for _, hanaMapping := range hanaMappings.Items {
+		if hanaMapping.Spec.AdminCredentials != obj.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&hanaMapping)})
+	}
+	return requests
 }
 
 func (r *HANAMappingReconciler) setStatusInProgress(ctx context.Context, hanaMapping *hanav1.HANAMapping) error {
 	condition := metav1.Condition{
-		Type:   conditionTypeReady,
-		Status: metav1.ConditionFalse,
-		Reason: conditionReasonInProgress,
+		Type:               conditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             conditionReasonInProgress,
+		ObservedGeneration: hanaMapping.Generation,
 	}
-	meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
-	return r.Client.Status().Update(ctx, hanaMapping)
+	return patchStatus(ctx, r.Client, hanaMapping, func() {
+		meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
+	})
 }
 
 func (r *HANAMappingReconciler) setStatusSucceeded(ctx context.Context, hanaMapping *hanav1.HANAMapping, mappingID *hanav1.MappingID) error {
 	condition := metav1.Condition{
-		Type:   conditionTypeReady,
-		Status: metav1.ConditionTrue,
-		Reason: conditionReasonSucceeded,
+		Type:               conditionTypeReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             conditionReasonSucceeded,
+		ObservedGeneration: hanaMapping.Generation,
 	}
-	meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
-	hanaMapping.Status.MappingID = mappingID
-	return r.Client.Status().Update(ctx, hanaMapping)
+	return patchStatus(ctx, r.Client, hanaMapping, func() {
+		meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
+		meta.RemoveStatusCondition(&hanaMapping.Status.Conditions, conditionTypeRemapping)
+		if meta.IsStatusConditionTrue(hanaMapping.Status.Conditions, conditionTypeClusterIDMigration) {
+			meta.SetStatusCondition(&hanaMapping.Status.Conditions, metav1.Condition{
+				Type:    conditionTypeClusterIDMigration,
+				Status:  metav1.ConditionFalse,
+				Reason:  conditionReasonMigrated,
+				Message: fmt.Sprintf("migrated to cluster ID %s", mappingID.PrimaryID),
+			})
+		}
+		hanaMapping.Status.MappingID = mappingID
+		hanaMapping.Status.PendingMappingID = nil
+		hanaMapping.Status.Operations = nil
+		hanaMapping.Status.ClusterID = ""
+		if mappingID.Platform == hanav1.PlatformKubernetes {
+			hanaMapping.Status.ClusterID = mappingID.PrimaryID
+		}
+	})
+}
+
+func (r *HANAMappingReconciler) setStatusRemapping(ctx context.Context, hanaMapping *hanav1.HANAMapping, oldMappingID, newMappingID *hanav1.MappingID) error {
+	condition := metav1.Condition{
+		Type:    conditionTypeRemapping,
+		Status:  metav1.ConditionTrue,
+		Reason:  conditionReasonInProgress,
+		Message: fmt.Sprintf("replacing mapping %s/%s by %s/%s", oldMappingID.PrimaryID, oldMappingID.SecondaryID, newMappingID.PrimaryID, newMappingID.SecondaryID),
+	}
+	return patchStatus(ctx, r.Client, hanaMapping, func() {
+		meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
+		hanaMapping.Status.PendingMappingID = newMappingID
+		if !hasOperation(hanaMapping.Status.Operations, hanav1.InventoryOperationCreate, newMappingID) {
+			hanaMapping.Status.Operations = append(hanaMapping.Status.Operations, newInventoryOperation(hanav1.InventoryOperationCreate, newMappingID))
+		}
+	})
+}
+
+// setStatusMappingRemoved records that the mapping was deleted to change its
+// isDefault flag but could not be recreated. The mapping ID is journaled as a
+// create operation instead, so that the next sync recreates it without
+// treating it as foreign, and a deletion still cleans it up.
+func (r *HANAMappingReconciler) setStatusMappingRemoved(ctx context.Context, hanaMapping *hanav1.HANAMapping, mappingID *hanav1.MappingID) error {
+	return patchStatus(ctx, r.Client, hanaMapping, func() {
+		hanaMapping.Status.MappingID = nil
+		hanaMapping.Status.ClusterID = ""
+		if !hasOperation(hanaMapping.Status.Operations, hanav1.InventoryOperationCreate, mappingID) {
+			hanaMapping.Status.Operations = append(hanaMapping.Status.Operations, newInventoryOperation(hanav1.InventoryOperationCreate, mappingID))
+		}
+	})
 }
 
-func (r *HANAMappingReconciler) setStatusFailed(ctx context.Context, hanaMapping *hanav1.HANAMapping, err error) error {
+func (r *HANAMappingReconciler) setStatusFailed(ctx context.Context, hanaMapping *hanav1.HANAMapping, reason string, err error) error {
 	condition := metav1.Condition{
-		Type:    conditionTypeReady,
-		Status:  metav1.ConditionFalse,
-		Reason:  conditionReasonFailed,
-		Message: err.Error(),
+		Type:               conditionTypeReady,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            err.Error(),
+		ObservedGeneration: hanaMapping.Generation,
 	}
-	meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
-	return r.Client.Status().Update(ctx, hanaMapping)
+	return patchStatus(ctx, r.Client, hanaMapping, func() {
+		meta.SetStatusCondition(&hanaMapping.Status.Conditions, condition)
+	})
 }
@@ -25,10 +25,17 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
@@ -37,7 +44,7 @@ import (
 
 const (
 	hanamappingName              = "test-hanamapping"
-	hanamappingServiceInstanceID = "test-serviceinstanceid"
+	hanamappingServiceInstanceID = "cf923d7d-7661-48f2-aaa2-d4dbb151a708"
 	hanamappingTargetNamespace   = "test-targetnamespace"
 )
 
@@ -218,6 +225,1126 @@ var _ = Describe("HANAMapping Controller", func() {
 			Expect(hanamapping.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
 			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonFailed))
 		})
+
+		It("should fail to reconcile a mapping which exists but is not owned", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.ListMappingsReturns([]inventory.Mapping{newInventoryMapping()}, nil)
+			inventoryClientStub.CreateMappingReturns(fmt.Errorf("create must not be called"))
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).To(MatchError(inventory.ErrMappingAlreadyExists))
+		})
+
+		It("should adopt a mapping which exists if annotated", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Annotations = map[string]string{hanav1.AdoptAnnotation: "true"}
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.ListMappingsReturns([]inventory.Mapping{newInventoryMapping()}, nil)
+			inventoryClientStub.CreateMappingReturns(fmt.Errorf("create must not be called"))
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.MappingID).NotTo(BeNil())
+			Expect(hanamapping.Annotations).NotTo(HaveKey(hanav1.AdoptAnnotation))
+		})
+
+		It("should not recreate an owned mapping which exists", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+			}}
+			hanamapping.Status.MappingID = &hanav1.MappingID{
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         clusterID,
+				SecondaryID:       hanamappingTargetNamespace,
+			}
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.ListMappingsReturns([]inventory.Mapping{newInventoryMapping()}, nil)
+			inventoryClientStub.CreateMappingReturns(fmt.Errorf("create must not be called"))
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("should requeue a mapping while the inventory is unavailable", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.CreateMappingReturns(inventory.ErrCircuitOpen)
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			result, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(result.RequeueAfter).Should(Equal(inventoryUnavailableRequeueInterval))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(len(hanamapping.Status.Conditions)).Should(Equal(1))
+			Expect(hanamapping.Status.Conditions[0].Type).Should(Equal(conditionTypeReady))
+			Expect(hanamapping.Status.Conditions[0].Status).Should(Equal(metav1.ConditionFalse))
+			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonInventoryUnavailable))
+		})
+	})
+
+	Describe("hanamapping CR cluster ID", func() {
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)
+			Expect(err).NotTo(HaveOccurred())
+
+			hanamapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		})
+
+		reconcileWith := func(clusterIDOptions ClusterIDOptions) (*inventoryClientStub, error) {
+			inventoryClientStub := &inventoryClientStub{}
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+				ClusterIDOptions:   clusterIDOptions,
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+			return inventoryClientStub, err
+		}
+
+		It("should record the cluster ID of the BTP operator config map", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			_, err := reconcileWith(ClusterIDOptions{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.ClusterID).To(Equal(clusterID))
+		})
+
+		It("should prefer the cluster ID of the spec", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.ClusterID = "spec-clusterid"
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub, err := reconcileWith(ClusterIDOptions{ClusterID: "flag-clusterid"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventoryClientStub.createdMappings[0].PrimaryID).To(Equal("spec-clusterid"))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.ClusterID).To(Equal("spec-clusterid"))
+		})
+
+		It("should use the UID of the kube-system namespace", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			kubeSystem := &corev1.Namespace{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: metav1.NamespaceSystem}, kubeSystem)).To(Succeed())
+
+			inventoryClientStub, err := reconcileWith(ClusterIDOptions{Source: ClusterIDSourceKubeSystem})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventoryClientStub.createdMappings[0].PrimaryID).To(Equal(string(kubeSystem.UID)))
+		})
+
+		It("should fail without a cluster ID", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub, err := reconcileWith(ClusterIDOptions{ConfigMapKey: "MISSING_CLUSTER_ID"})
+			Expect(err).To(HaveOccurred())
+			Expect(inventoryClientStub.createdMappings).To(BeEmpty())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonClusterIDMissing))
+		})
+
+		It("should fail with a malformed cluster ID", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.ClusterID = "cluster/id"
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			_, err := reconcileWith(ClusterIDOptions{})
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonClusterIDMissing))
+		})
+	})
+
+	Describe("hanamapping CR validation", func() {
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)
+			if err == nil {
+				Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+			}
+		})
+
+		It("should reject a service instance ID which is not a GUID", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.ServiceInstanceID = "test-serviceinstanceid"
+			Expect(k8sClient.Create(ctx, hanamapping)).To(MatchError(ContainSubstring("serviceInstanceID must be a GUID")))
+		})
+
+		It("should reject a target namespace which is not a DNS label", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.TargetNamespace = "Test_Namespace"
+			Expect(k8sClient.Create(ctx, hanamapping)).To(MatchError(ContainSubstring("targetNamespace must be a DNS label")))
+		})
+
+		It("should remove the finalizer of a stored HANAMapping which violates a newer rule", func() {
+			crdName := types.NamespacedName{Name: "hanamappings.hana.cloud.sap.com"}
+			setCRDSpec := func(spec map[string]interface{}) {
+				Eventually(func() error {
+					crd := &unstructured.Unstructured{}
+					crd.SetGroupVersionKind(customResourceDefinitionGVK)
+					if err := k8sClient.Get(ctx, crdName, crd); err != nil {
+						return err
+					}
+					if err := unstructured.SetNestedMap(crd.Object, runtime.DeepCopyJSON(spec), "spec"); err != nil {
+						return err
+					}
+					return k8sClient.Update(ctx, crd)
+				}).Should(Succeed())
+			}
+
+			crd := &unstructured.Unstructured{}
+			crd.SetGroupVersionKind(customResourceDefinitionGVK)
+			Expect(k8sClient.Get(ctx, crdName, crd)).To(Succeed())
+			spec, _, err := unstructured.NestedMap(crd.Object, "spec")
+			Expect(err).NotTo(HaveOccurred())
+			DeferCleanup(setCRDSpec, spec)
+
+			// Store a HANAMapping as it could have been created before the rules existed.
+			relaxed := runtime.DeepCopyJSON(spec)
+			versions, _, err := unstructured.NestedSlice(relaxed, "versions")
+			Expect(err).NotTo(HaveOccurred())
+			for _, version := range versions {
+				unstructured.RemoveNestedField(version.(map[string]interface{}), "schema", "openAPIV3Schema", "x-kubernetes-validations")
+			}
+			Expect(unstructured.SetNestedSlice(relaxed, versions, "versions")).To(Succeed())
+			setCRDSpec(relaxed)
+
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.ServiceInstanceID = "test-serviceinstanceid"
+			hanamapping.Finalizers = []string{finalizerName}
+			Eventually(func() error {
+				return k8sClient.Create(ctx, hanamapping)
+			}).Should(Succeed())
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionFalse,
+				Reason:             conditionReasonFailed,
+			}}
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+			setCRDSpec(spec)
+			Eventually(func() error {
+				probe := newHANAMapping(hanamappingName + "-probe")
+				probe.Spec.Mapping.ServiceInstanceID = "test-serviceinstanceid"
+				err := k8sClient.Create(ctx, probe)
+				if err == nil {
+					Expect(k8sClient.Delete(ctx, probe)).To(Succeed())
+				}
+				return err
+			}).Should(MatchError(ContainSubstring("serviceInstanceID must be a GUID")))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			hanamapping.Finalizers = nil
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Spec.Mapping.ServiceInstanceID = "other-serviceinstanceid"
+			Expect(k8sClient.Update(ctx, hanamapping)).To(MatchError(ContainSubstring("serviceInstanceID must be a GUID")))
+		})
+
+		It("should reject a primary ID on kubernetes", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.PrimaryID = clusterID
+			Expect(k8sClient.Create(ctx, hanamapping)).To(MatchError(ContainSubstring("primaryID and secondaryID are only supported on cloudfoundry")))
+		})
+
+		It("should accept a primary and secondary ID on cloudfoundry", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.Platform = hanav1.PlatformCloudFoundry
+			hanamapping.Spec.Mapping.PrimaryID = "0a4d6fd8-b8e6-4c47-b5bb-7d1c4e8e7d4f"
+			hanamapping.Spec.Mapping.SecondaryID = "5f9c2a3e-2c1d-4b7e-9d8a-3e6f1b2c4d5e"
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+		})
+
+		It("should reject an empty admin api access secret", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.AdminAPIAccessSecret.Name = ""
+			Expect(k8sClient.Create(ctx, hanamapping)).To(MatchError(ContainSubstring("adminAPIAccessSecret must reference a secret")))
+		})
+
+		It("should reject a new service instance ID once mapped", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+			}}
+			hanamapping.Status.MappingID = &hanav1.MappingID{
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         clusterID,
+				SecondaryID:       hanamappingTargetNamespace,
+			}
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Spec.Mapping.ServiceInstanceID = "0c9a3b4e-0f6b-4b4e-9d5a-2f0c2c0a6f11"
+			Expect(k8sClient.Update(ctx, hanamapping)).To(MatchError(ContainSubstring("serviceInstanceID is immutable")))
+
+			hanamapping.Spec.AllowRemap = true
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+		})
+	})
+
+	Describe("remap hanamapping CR", func() {
+		const oldTargetNamespace = "test-oldtargetnamespace"
+
+		var stub *mappingStoreStub
+
+		BeforeEach(func() {
+			stub = &mappingStoreStub{mappings: map[string]inventory.Mapping{
+				clusterID + "/" + oldTargetNamespace: {Platform: hanav1.PlatformKubernetes, PrimaryID: clusterID, SecondaryID: oldTargetNamespace},
+			}}
+		})
+
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)
+			Expect(err).NotTo(HaveOccurred())
+
+			hanamapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		})
+
+		createRemappedHANAMapping := func(pendingMappingID *hanav1.MappingID) *hanav1.HANAMapping {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+			}}
+			hanamapping.Status.MappingID = &hanav1.MappingID{
+				Platform:          hanav1.PlatformKubernetes,
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         clusterID,
+				SecondaryID:       oldTargetNamespace,
+			}
+			hanamapping.Status.PendingMappingID = pendingMappingID
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+			return hanamapping
+		}
+
+		reconcileRemap := func() error {
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return stub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+			return err
+		}
+
+		It("should create the new mapping before deleting the old one", func() {
+			hanamapping := createRemappedHANAMapping(nil)
+
+			Expect(reconcileRemap()).To(Succeed())
+
+			Expect(stub.mappings).To(HaveKey(clusterID + "/" + hanamappingTargetNamespace))
+			Expect(stub.mappings).NotTo(HaveKey(clusterID + "/" + oldTargetNamespace))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.MappingID.SecondaryID).To(Equal(hanamappingTargetNamespace))
+			Expect(hanamapping.Status.PendingMappingID).To(BeNil())
+			Expect(meta.FindStatusCondition(hanamapping.Status.Conditions, conditionTypeRemapping)).To(BeNil())
+		})
+
+		It("should keep the old mapping if the new one cannot be created", func() {
+			hanamapping := createRemappedHANAMapping(nil)
+			stub.createErr = fmt.Errorf("inventory error")
+
+			Expect(reconcileRemap()).NotTo(Succeed())
+
+			Expect(stub.mappings).To(HaveKey(clusterID + "/" + oldTargetNamespace))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.MappingID.SecondaryID).To(Equal(oldTargetNamespace))
+			Expect(hanamapping.Status.PendingMappingID.SecondaryID).To(Equal(hanamappingTargetNamespace))
+			Expect(meta.IsStatusConditionTrue(hanamapping.Status.Conditions, conditionTypeRemapping)).To(BeTrue())
+		})
+
+		It("should resume an interrupted remap", func() {
+			pendingMappingID := &hanav1.MappingID{
+				Platform:          hanav1.PlatformKubernetes,
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         clusterID,
+				SecondaryID:       hanamappingTargetNamespace,
+			}
+			hanamapping := createRemappedHANAMapping(pendingMappingID)
+			stub.mappings[clusterID+"/"+hanamappingTargetNamespace] = newInventoryMapping()
+
+			Expect(reconcileRemap()).To(Succeed())
+
+			Expect(stub.mappings).NotTo(HaveKey(clusterID + "/" + oldTargetNamespace))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(*hanamapping.Status.MappingID).To(Equal(*pendingMappingID))
+			Expect(hanamapping.Status.PendingMappingID).To(BeNil())
+		})
+
+		It("should not take over a new mapping which is not owned", func() {
+			createRemappedHANAMapping(nil)
+			stub.mappings[clusterID+"/"+hanamappingTargetNamespace] = newInventoryMapping()
+
+			Expect(reconcileRemap()).To(MatchError(inventory.ErrMappingAlreadyExists))
+
+			Expect(stub.mappings).To(HaveKey(clusterID + "/" + oldTargetNamespace))
+		})
+	})
+
+	Describe("migrate hanamapping CR to a new cluster ID", func() {
+		var stub *mappingStoreStub
+
+		BeforeEach(func() {
+			stub = &mappingStoreStub{mappings: map[string]inventory.Mapping{
+				oldClusterID + "/" + hanamappingTargetNamespace: {Platform: hanav1.PlatformKubernetes, PrimaryID: oldClusterID, SecondaryID: hanamappingTargetNamespace},
+			}}
+
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+			}}
+			hanamapping.Status.MappingID = &hanav1.MappingID{
+				Platform:          hanav1.PlatformKubernetes,
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         oldClusterID,
+				SecondaryID:       hanamappingTargetNamespace,
+			}
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+
+			hanamapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		})
+
+		reconcileMigration := func() error {
+			controllerReconciler := &HANAMappingReconciler{
+				Client:              k8sClient,
+				Log:                 log,
+				Scheme:              k8sClient.Scheme(),
+				GetInventoryClient:  func(adminAPIAccessBinding inventory.Binding) inventory.Client { return stub },
+				ClusterIDMigrations: make(chan event.GenericEvent),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+			return err
+		}
+
+		It("should keep the mapping until its migration is started", func() {
+			Expect(reconcileMigration()).To(Succeed())
+
+			Expect(stub.mappings).To(HaveKey(oldClusterID + "/" + hanamappingTargetNamespace))
+			Expect(stub.mappings).NotTo(HaveKey(clusterID + "/" + hanamappingTargetNamespace))
+
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.MappingID.PrimaryID).To(Equal(oldClusterID))
+		})
+
+		It("should remap the mapping once its migration is started", func() {
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			meta.SetStatusCondition(&hanamapping.Status.Conditions, metav1.Condition{
+				Type:   conditionTypeClusterIDMigration,
+				Status: metav1.ConditionTrue,
+				Reason: conditionReasonMigrating,
+			})
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(reconcileMigration()).To(Succeed())
+
+			Expect(stub.mappings).To(HaveKey(clusterID + "/" + hanamappingTargetNamespace))
+			Expect(stub.mappings).NotTo(HaveKey(oldClusterID + "/" + hanamappingTargetNamespace))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.MappingID.PrimaryID).To(Equal(clusterID))
+			condition := meta.FindStatusCondition(hanamapping.Status.Conditions, conditionTypeClusterIDMigration)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(conditionReasonMigrated))
+		})
+	})
+
+	Describe("journaled hanamapping CR", func() {
+		const abandonedTargetNamespace = "test-abandonedtargetnamespace"
+
+		var stub *mappingStoreStub
+
+		BeforeEach(func() {
+			stub = &mappingStoreStub{mappings: map[string]inventory.Mapping{}}
+		})
+
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)
+			Expect(err).NotTo(HaveOccurred())
+
+			hanamapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		})
+
+		createJournaledHANAMapping := func(secondaryID string) *hanav1.HANAMapping {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionFalse,
+				Reason:             conditionReasonInProgress,
+			}}
+			hanamapping.Status.Operations = []hanav1.InventoryOperation{{
+				Type: hanav1.InventoryOperationCreate,
+				MappingID: hanav1.MappingID{
+					Platform:          hanav1.PlatformKubernetes,
+					ServiceInstanceID: hanamappingServiceInstanceID,
+					PrimaryID:         clusterID,
+					SecondaryID:       secondaryID,
+				},
+				StartedAt: metav1.Now(),
+			}}
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+			stub.mappings[clusterID+"/"+secondaryID] = inventory.Mapping{Platform: hanav1.PlatformKubernetes, PrimaryID: clusterID, SecondaryID: secondaryID}
+			return hanamapping
+		}
+
+		reconcileJournaled := func() error {
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return stub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+			return err
+		}
+
+		It("should adopt a mapping whose creation was journaled", func() {
+			hanamapping := createJournaledHANAMapping(hanamappingTargetNamespace)
+
+			Expect(reconcileJournaled()).To(Succeed())
+
+			Expect(stub.mappings).To(HaveKey(clusterID + "/" + hanamappingTargetNamespace))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.MappingID.SecondaryID).To(Equal(hanamappingTargetNamespace))
+			Expect(hanamapping.Status.Operations).To(BeEmpty())
+		})
+
+		It("should delete a journaled mapping which is no longer desired", func() {
+			hanamapping := createJournaledHANAMapping(abandonedTargetNamespace)
+
+			Expect(reconcileJournaled()).To(Succeed())
+
+			Expect(stub.mappings).To(HaveKey(clusterID + "/" + hanamappingTargetNamespace))
+			Expect(stub.mappings).NotTo(HaveKey(clusterID + "/" + abandonedTargetNamespace))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.Operations).To(BeEmpty())
+		})
+	})
+
+	Describe("hanamapping CR deletion policy", func() {
+		reconcileDeletion := func(deletionPolicy string, defaultDeletionPolicy ...string) *inventoryClientStub {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.DeletionPolicy = deletionPolicy
+			hanamapping.ObjectMeta.Finalizers = []string{finalizerName}
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+			}}
+			hanamapping.Status.MappingID = &hanav1.MappingID{
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         clusterID,
+				SecondaryID:       hanamappingTargetNamespace,
+			}
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.ListMappingsReturns([]inventory.Mapping{newInventoryMapping()}, nil)
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+			if len(defaultDeletionPolicy) > 0 {
+				controllerReconciler.DefaultDeletionPolicy = defaultDeletionPolicy[0]
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+
+			return inventoryClientStub
+		}
+
+		It("should delete the mapping by default", func() {
+			Expect(reconcileDeletion("").deletedMappings).To(Equal(1))
+		})
+
+		It("should keep the mapping with the orphan deletion policy", func() {
+			Expect(reconcileDeletion(hanav1.DeletionPolicyOrphan).deletedMappings).To(Equal(0))
+		})
+
+		It("should apply the default deletion policy of the operator", func() {
+			Expect(reconcileDeletion("", hanav1.DeletionPolicyOrphan).deletedMappings).To(Equal(0))
+			Expect(reconcileDeletion(hanav1.DeletionPolicyDelete, hanav1.DeletionPolicyOrphan).deletedMappings).To(Equal(1))
+		})
+	})
+
+	Describe("cloudfoundry hanamapping CR", func() {
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)
+			Expect(err).NotTo(HaveOccurred())
+
+			hanamapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		})
+
+		It("should succeed to reconcile a mapping", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.Platform = hanav1.PlatformCloudFoundry
+			hanamapping.Spec.Mapping.TargetNamespace = ""
+			hanamapping.Spec.Mapping.PrimaryID = "test-orgid"
+			hanamapping.Spec.Mapping.SecondaryID = "test-spaceid"
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventoryClientStub.createdMappings).To(Equal([]inventory.Mapping{{
+				Platform:    hanav1.PlatformCloudFoundry,
+				PrimaryID:   "test-orgid",
+				SecondaryID: "test-spaceid",
+			}}))
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Spec.Mapping.TargetNamespace).Should(BeEmpty())
+			Expect(*hanamapping.Status.MappingID).Should(Equal(hanav1.MappingID{
+				Platform:          hanav1.PlatformCloudFoundry,
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         "test-orgid",
+				SecondaryID:       "test-spaceid",
+			}))
+		})
+
+		It("should fail to reconcile a mapping without IDs", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.Platform = hanav1.PlatformCloudFoundry
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return &inventoryClientStub{} },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonInvalidMapping))
+		})
+	})
+
+	Describe("default hanamapping CR", func() {
+		const otherHANAMappingName = "test-hanamapping-other"
+
+		AfterEach(func() {
+			for _, name := range []string{hanamappingName, otherHANAMappingName} {
+				hanamapping := &hanav1.HANAMapping{}
+				err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: name}, hanamapping)
+				if err != nil {
+					continue
+				}
+
+				hanamapping.ObjectMeta.Finalizers = []string{}
+				Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+				Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+			}
+		})
+
+		It("should create a default mapping", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.IsDefault = true
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventoryClientStub.createdMappings).To(HaveLen(1))
+			Expect(inventoryClientStub.createdMappings[0].IsDefault).To(BeTrue())
+		})
+
+		It("should recreate a mapping whose isDefault flag differs", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.IsDefault = true
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			hanamapping.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+			}}
+			hanamapping.Status.MappingID = &hanav1.MappingID{
+				ServiceInstanceID: hanamappingServiceInstanceID,
+				PrimaryID:         clusterID,
+				SecondaryID:       hanamappingTargetNamespace,
+			}
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.ListMappingsReturns([]inventory.Mapping{newInventoryMapping()}, nil)
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventoryClientStub.deletedMappings).To(Equal(1))
+			Expect(inventoryClientStub.createdMappings).To(HaveLen(1))
+			Expect(inventoryClientStub.createdMappings[0].IsDefault).To(BeTrue())
+		})
+
+		It("should fail to reconcile a second default mapping for a namespace", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.Mapping.IsDefault = true
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+			otherHANAMapping := newHANAMapping(otherHANAMappingName)
+			otherHANAMapping.Spec.Mapping.IsDefault = true
+			Expect(k8sClient.Create(ctx, otherHANAMapping)).To(Succeed())
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return &inventoryClientStub{} },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: otherHANAMappingName},
+			})
+
+			Expect(err).To(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: otherHANAMappingName}, otherHANAMapping)).To(Succeed())
+			Expect(otherHANAMapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonDefaultConflict))
+		})
+	})
+
+	Describe("cross-namespace admin api access secret", func() {
+		const (
+			grantNamespace = "test-grantnamespace"
+			grantName      = "test-grant"
+		)
+
+		BeforeEach(func() {
+			err := k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: grantNamespace}})
+			if !errors.IsAlreadyExists(err) {
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Namespace = grantNamespace
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName}, hanamapping); err == nil {
+				hanamapping.ObjectMeta.Finalizers = []string{}
+				Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+				Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+			}
+
+			grant := &hanav1.HANAMappingCredentialGrant{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: grantName}, grant); err == nil {
+				Expect(k8sClient.Delete(ctx, grant)).To(Succeed())
+			}
+		})
+
+		It("should fail to reconcile a mapping without a credential grant", func() {
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return &inventoryClientStub{} },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).To(HaveOccurred())
+
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonCredentialsNotPermitted))
+		})
+
+		It("should succeed to reconcile a mapping with a credential grant", func() {
+			Expect(k8sClient.Create(ctx, &hanav1.HANAMappingCredentialGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      grantName,
+				},
+				Spec: hanav1.HANAMappingCredentialGrantSpec{
+					From: []hanav1.CredentialGrantFrom{{Namespace: grantNamespace}},
+					To:   []hanav1.CredentialGrantTo{{Name: adminAPIAccessSecret}},
+				},
+			})).To(Succeed())
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return &inventoryClientStub{} },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonSucceeded))
+		})
+
+		It("should orphan the mapping if the credential grant was revoked before the deletion", func() {
+			grant := &hanav1.HANAMappingCredentialGrant{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: testNamespace,
+					Name:      grantName,
+				},
+				Spec: hanav1.HANAMappingCredentialGrantSpec{
+					From: []hanav1.CredentialGrantFrom{{Namespace: grantNamespace}},
+					To:   []hanav1.CredentialGrantTo{{Name: adminAPIAccessSecret}},
+				},
+			}
+			Expect(k8sClient.Create(ctx, grant)).To(Succeed())
+
+			inventoryClientStub := &inventoryClientStub{}
+			recorder := record.NewFakeRecorder(10)
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				Recorder:           recorder,
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventoryClientStub.createdMappings).To(HaveLen(1))
+
+			Expect(k8sClient.Delete(ctx, grant)).To(Succeed())
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName},
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(inventoryClientStub.deletedMappings).To(Equal(0))
+			Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonMappingOrphaned)))
+
+			err = k8sClient.Get(ctx, types.NamespacedName{Namespace: grantNamespace, Name: hanamappingName}, hanamapping)
+			Expect(errors.IsNotFound(err)).To(BeTrue())
+		})
+	})
+
+	Describe("hanamapping CR with admin credentials", func() {
+		BeforeEach(func() {
+			Expect(k8sClient.Create(ctx, newHANAAdminCredentials(adminCredentialsName))).To(Succeed())
+
+			hanamapping := newHANAMapping(hanamappingName)
+			hanamapping.Spec.AdminAPIAccessSecret = nil
+			hanamapping.Spec.AdminCredentials = adminCredentialsName
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+
+			hanamapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+
+			credentials := &hanav1.HANAAdminCredentials{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName}, credentials)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, credentials)).To(Succeed())
+		})
+
+		It("should fail to reconcile a mapping while the credentials are not ready", func() {
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return &inventoryClientStub{} },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).To(HaveOccurred())
+
+			hanamapping := &hanav1.HANAMapping{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(hanamapping.Status.Conditions[0].Reason).Should(Equal(conditionReasonCredentialsNotReady))
+		})
+
+		It("should reconcile a mapping with the binding of ready credentials", func() {
+			credentials := &hanav1.HANAAdminCredentials{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName}, credentials)).To(Succeed())
+			credentials.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+				ObservedGeneration: credentials.Generation,
+			}}
+			Expect(k8sClient.Status().Update(ctx, credentials)).To(Succeed())
+
+			var usedBinding inventory.Binding
+			controllerReconciler := &HANAMappingReconciler{
+				Client: k8sClient,
+				Log:    log,
+				Scheme: k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client {
+					usedBinding = adminAPIAccessBinding
+					return &inventoryClientStub{}
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usedBinding.BaseURL).To(Equal(adminAPIAccessBaseURL))
+			Expect(usedBinding.ProxyURL).To(Equal("http://proxy:3128"))
+		})
+
+		It("should reconcile a mapping with the stored binding of ready credentials", func() {
+			credentials := &hanav1.HANAAdminCredentials{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName}, credentials)).To(Succeed())
+			credentials.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+				ObservedGeneration: credentials.Generation,
+			}}
+			Expect(k8sClient.Status().Update(ctx, credentials)).To(Succeed())
+
+			bindings := NewBindingStore()
+			bindings.Set(credentials, inventory.Binding{BaseURL: "https://stored.example.com"})
+
+			var usedBinding inventory.Binding
+			controllerReconciler := &HANAMappingReconciler{
+				Client:   k8sClient,
+				Log:      log,
+				Scheme:   k8sClient.Scheme(),
+				Bindings: bindings,
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client {
+					usedBinding = adminAPIAccessBinding
+					return &inventoryClientStub{}
+				},
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(usedBinding.BaseURL).To(Equal("https://stored.example.com"))
+		})
+
+		It("should fail to reconcile a mapping while the credentials are ready for an older generation", func() {
+			credentials := &hanav1.HANAAdminCredentials{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: adminCredentialsName}, credentials)).To(Succeed())
+			credentials.Status.Conditions = []metav1.Condition{{
+				LastTransitionTime: metav1.Now(),
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+				ObservedGeneration: credentials.Generation - 1,
+			}}
+			Expect(k8sClient.Status().Update(ctx, credentials)).To(Succeed())
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return &inventoryClientStub{} },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).To(HaveOccurred())
+		})
 	})
 
 	Describe("delete hanamapping CR", func() {
@@ -256,6 +1383,7 @@ var _ = Describe("HANAMapping Controller", func() {
 
 		It("should succeed to delete a mapping", func() {
 			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.ListMappingsReturns([]inventory.Mapping{newInventoryMapping()}, nil)
 			inventoryClientStub.DeleteMappingReturns(nil)
 
 			controllerReconciler := &HANAMappingReconciler{
@@ -274,6 +1402,7 @@ var _ = Describe("HANAMapping Controller", func() {
 
 		It("should fail to delete a mapping", func() {
 			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.ListMappingsReturns([]inventory.Mapping{newInventoryMapping()}, nil)
 			inventoryClientStub.DeleteMappingReturns(fmt.Errorf("inventory error"))
 
 			controllerReconciler := &HANAMappingReconciler{
@@ -289,6 +1418,61 @@ var _ = Describe("HANAMapping Controller", func() {
 
 			Expect(err).To(HaveOccurred())
 		})
+
+		It("should skip deleting a mapping which no longer exists", func() {
+			inventoryClientStub := &inventoryClientStub{}
+			inventoryClientStub.DeleteMappingReturns(fmt.Errorf("inventory error"))
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client:             k8sClient,
+				Log:                log,
+				Scheme:             k8sClient.Scheme(),
+				GetInventoryClient: func(adminAPIAccessBinding inventory.Binding) inventory.Client { return inventoryClientStub },
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: hanamappingName},
+			})
+
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Describe("concurrently modified hanamapping CR", func() {
+		AfterEach(func() {
+			hanamapping := &hanav1.HANAMapping{}
+			err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)
+			Expect(err).NotTo(HaveOccurred())
+
+			hanamapping.ObjectMeta.Finalizers = []string{}
+			Expect(k8sClient.Update(ctx, hanamapping)).To(Succeed())
+
+			Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		})
+
+		It("should keep a condition written since the mapping was read", func() {
+			hanamapping := newHANAMapping(hanamappingName)
+			Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+			staleHANAMapping := hanamapping.DeepCopy()
+
+			meta.SetStatusCondition(&hanamapping.Status.Conditions, metav1.Condition{
+				Type:   conditionTypeClusterIDMigration,
+				Status: metav1.ConditionFalse,
+				Reason: conditionReasonMigrated,
+			})
+			Expect(k8sClient.Status().Update(ctx, hanamapping)).To(Succeed())
+
+			controllerReconciler := &HANAMappingReconciler{
+				Client: k8sClient,
+				Log:    log,
+				Scheme: k8sClient.Scheme(),
+			}
+			Expect(controllerReconciler.setStatusFailed(ctx, staleHANAMapping, conditionReasonFailed, fmt.Errorf("inventory error"))).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: hanamappingName}, hanamapping)).To(Succeed())
+			Expect(meta.FindStatusCondition(hanamapping.Status.Conditions, conditionTypeClusterIDMigration)).NotTo(BeNil())
+			Expect(meta.FindStatusCondition(hanamapping.Status.Conditions, conditionTypeReady).Reason).To(Equal(conditionReasonFailed))
+		})
 	})
 })
 
@@ -303,7 +1487,7 @@ func newHANAMapping(name string) *hanav1.HANAMapping {
 				Namespace: testNamespace,
 				Name:      btpOperatorConfigmap,
 			},
-			AdminAPIAccessSecret: hanav1.NamespacedName{
+			AdminAPIAccessSecret: &hanav1.NamespacedName{
 				Namespace: testNamespace,
 				Name:      adminAPIAccessSecret,
 			},
@@ -316,6 +1500,14 @@ func newHANAMapping(name string) *hanav1.HANAMapping {
 	return hanamapping
 }
 
+func newInventoryMapping() inventory.Mapping {
+	return inventory.Mapping{
+		Platform:    "kubernetes",
+		PrimaryID:   clusterID,
+		SecondaryID: hanamappingTargetNamespace,
+	}
+}
+
 type inventoryClientStub struct {
 	listMappingsReturns struct {
 		mappings []inventory.Mapping
@@ -323,6 +1515,9 @@ type inventoryClientStub struct {
 	}
 	createMappingsReturns error
 	deleteMappingsReturns error
+
+	createdMappings []inventory.Mapping
+	deletedMappings int
 }
 
 func (c *inventoryClientStub) ListMappingsReturns(mappings []inventory.Mapping, err error) {
@@ -347,10 +1542,25 @@ func (c *inventoryClientStub) ListMappings(ctx context.Context, serviceInstanceI
 	return c.listMappingsReturns.mappings, c.listMappingsReturns.err
 }
 
+func (c *inventoryClientStub) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*inventory.Mapping, error) {
+	if c.listMappingsReturns.err != nil {
+		return nil, c.listMappingsReturns.err
+	}
+	for i := range c.listMappingsReturns.mappings {
+		mapping := c.listMappingsReturns.mappings[i]
+		if inventory.SamePlatform(mapping.Platform, platform) && mapping.PrimaryID == primaryID && mapping.SecondaryID == secondaryID {
+			return &mapping, nil
+		}
+	}
+	return nil, inventory.ErrMappingNotFound
+}
+
 func (c *inventoryClientStub) CreateMapping(ctx context.Context, serviceInstanceID string, mapping inventory.Mapping) error {
+	c.createdMappings = append(c.createdMappings, mapping)
 	return c.createMappingsReturns
 }
 
 func (c *inventoryClientStub) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
+	c.deletedMappings++
 	return c.deleteMappingsReturns
 }
@@ -0,0 +1,312 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+const (
+	moduleFinalizerName = "hanamappingoperators.hana.cloud.sap.com/finalizer"
+
+	conditionReasonPrerequisitesMissing = "PrerequisitesMissing"
+	conditionReasonMappingsNotReady     = "MappingsNotReady"
+	conditionReasonMappingsFailed       = "MappingsFailed"
+	conditionReasonDeleting             = "Deleting"
+
+	moduleRequeueInterval = 30 * time.Second
+)
+
+var customResourceDefinitionGVK = schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1", Kind: "CustomResourceDefinition"}
+
+// HANAMappingOperatorReconciler reconciles a HANAMappingOperator object
+type HANAMappingOperatorReconciler struct {
+	Client client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	// ClusterIDOptions decide whether the BTP operator config map is a
+	// prerequisite, it is only read if the cluster ID is taken from it.
+	ClusterIDOptions ClusterIDOptions
+	// ClusterHANAMappings is set if the ClusterHANAMapping controller runs.
+	// Their CRD is required then and they are counted like HANAMappings.
+	ClusterHANAMappings bool
+	// HANAMappingSets is set if the HANAMappingSet controller runs. Their CRD
+	// and the ServiceInstance CRD of the BTP operator are required then.
+	HANAMappingSets         bool
+	MaxConcurrentReconciles int
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HANAMappingOperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&hanav1.HANAMappingOperator{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&hanav1.HANAMapping{}, handler.EnqueueRequestsFromMapFunc(r.hanaMappingOperators))
+	if r.ClusterHANAMappings {
+		b = b.Watches(&hanav1.ClusterHANAMapping{}, handler.EnqueueRequestsFromMapFunc(r.hanaMappingOperators))
+	}
+	return b.WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
+
+// requiredCustomResourceDefinitions returns the CRDs which must be established
+// for the enabled controllers, including the ones of the BTP operator.
+func (r *HANAMappingOperatorReconciler) requiredCustomResourceDefinitions() []string {
+	names := []string{
+		"hanamappings.hana.cloud.sap.com",
+		"hanaadmincredentials.hana.cloud.sap.com",
+		"hanamappingcredentialgrants.hana.cloud.sap.com",
+		"hanainstanceinventories.hana.cloud.sap.com",
+	}
+	if r.ClusterHANAMappings {
+		names = append(names, "clusterhanamappings.hana.cloud.sap.com")
+	}
+	if r.HANAMappingSets {
+		names = append(names, "hanamappingsets.hana.cloud.sap.com", "serviceinstances.services.cloud.sap.com")
+	}
+	return append(names, "servicebindings.services.cloud.sap.com")
+}
+
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingoperators,verbs=get;list;watch;update;patch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingoperators/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingoperators/finalizers,verbs=update
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappings,verbs=get;list;watch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=clusterhanamappings,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch
+//+kubebuilder:rbac:groups=apiextensions.k8s.io,resources=customresourcedefinitions,verbs=get
+
+// Reconcile validates the prerequisites of the operator and reports them
+// together with the health of all HANAMappings and ClusterHANAMappings in
+// status.state, as expected by the Kyma Lifecycle Manager. Deletion is blocked
+// until all of them are deleted, so that their inventory mappings are not
+// orphaned.
+func (r *HANAMappingOperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("hanamappingoperator", req.NamespacedName).WithValues("correlation_id", uuid.New().String())
+
+	module := &hanav1.HANAMappingOperator{}
+	if err := r.Client.Get(ctx, req.NamespacedName, module); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	log.Info(fmt.Sprintf("got hanamappingoperator gen %d", module.Generation))
+	module = module.DeepCopy()
+
+	counts, err := r.countMappings(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if !module.DeletionTimestamp.IsZero() {
+		if !controllerutil.ContainsFinalizer(module, moduleFinalizerName) {
+			return ctrl.Result{}, nil
+		}
+		if counts.total > 0 {
+			message := fmt.Sprintf("waiting for %d HANAMappings to be deleted", counts.total)
+			return ctrl.Result{RequeueAfter: moduleRequeueInterval},
+				r.setState(ctx, module, hanav1.StateDeleting, metav1.ConditionFalse, conditionReasonDeleting, message, counts)
+		}
+		log.Info("removing finalizer")
+		return ctrl.Result{}, patchObject(ctx, r.Client, module, func() {
+			controllerutil.RemoveFinalizer(module, moduleFinalizerName)
+		})
+	}
+
+	if !controllerutil.ContainsFinalizer(module, moduleFinalizerName) {
+		if err := patchObject(ctx, r.Client, module, func() {
+			controllerutil.AddFinalizer(module, moduleFinalizerName)
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.checkPrerequisites(ctx, module); err != nil {
+		if statusErr := r.setState(ctx, module, hanav1.StateError, metav1.ConditionFalse, failureReason(err), err.Error(), counts); statusErr != nil {
+			log.Error(statusErr, "failed to record missing prerequisites")
+		}
+		if _, ok := err.(*conditionError); ok {
+			return ctrl.Result{RequeueAfter: moduleRequeueInterval}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	switch {
+	case counts.failed > 0:
+		message := fmt.Sprintf("%d of %d HANAMappings failed", counts.failed, counts.total)
+		return ctrl.Result{}, r.setState(ctx, module, hanav1.StateError, metav1.ConditionFalse, conditionReasonMappingsFailed, message, counts)
+	case counts.ready < counts.total:
+		message := fmt.Sprintf("%d of %d HANAMappings are ready", counts.ready, counts.total)
+		return ctrl.Result{}, r.setState(ctx, module, hanav1.StateProcessing, metav1.ConditionFalse, conditionReasonMappingsNotReady, message, counts)
+	}
+	return ctrl.Result{}, r.setState(ctx, module, hanav1.StateReady, metav1.ConditionTrue, conditionReasonSucceeded, "", counts)
+}
+
+// checkPrerequisites returns a conditionError if the BTP operator config map
+// the cluster ID is read from or one of the required CRDs is missing.
+func (r *HANAMappingOperatorReconciler) checkPrerequisites(ctx context.Context, module *hanav1.HANAMappingOperator) error {
+	if len(r.ClusterIDOptions.ClusterID) == 0 && r.ClusterIDOptions.Source != ClusterIDSourceKubeSystem {
+		btpOperatorConfigmap := r.ClusterIDOptions.DefaultBTPOperatorConfigmap
+		if module.Spec.BTPOperatorConfigmap != nil {
+			btpOperatorConfigmap = *module.Spec.BTPOperatorConfigmap
+		}
+		cm := &corev1.ConfigMap{}
+		if err := r.Client.Get(ctx, types.NamespacedName{Namespace: btpOperatorConfigmap.Namespace, Name: btpOperatorConfigmap.Name}, cm); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			return &conditionError{
+				reason:  conditionReasonPrerequisitesMissing,
+				message: fmt.Sprintf("BTP operator config map %s/%s not found", btpOperatorConfigmap.Namespace, btpOperatorConfigmap.Name),
+			}
+		}
+	}
+
+	for _, name := range r.requiredCustomResourceDefinitions() {
+		crd := &unstructured.Unstructured{}
+		crd.SetGroupVersionKind(customResourceDefinitionGVK)
+		if err := r.Client.Get(ctx, types.NamespacedName{Name: name}, crd); err != nil {
+			if !errors.IsNotFound(err) {
+				return err
+			}
+			return &conditionError{
+				reason:  conditionReasonPrerequisitesMissing,
+				message: fmt.Sprintf("CRD %s not found", name),
+			}
+		}
+		if !isCustomResourceDefinitionEstablished(crd) {
+			return &conditionError{
+				reason:  conditionReasonPrerequisitesMissing,
+				message: fmt.Sprintf("CRD %s is not established", name),
+			}
+		}
+	}
+	return nil
+}
+
+func isCustomResourceDefinitionEstablished(crd *unstructured.Unstructured) bool {
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == "Established" && condition["status"] == string(metav1.ConditionTrue) {
+			return true
+		}
+	}
+	return false
+}
+
+// mappingCounts aggregates the health of HANAMappings and ClusterHANAMappings.
+type mappingCounts struct {
+	total, ready, failed int
+}
+
+// add counts a mapping as ready or as failed at its current generation by its
+// Ready condition.
+func (c *mappingCounts) add(conditions []metav1.Condition, generation int64) {
+	c.total++
+	condition := meta.FindStatusCondition(conditions, conditionTypeReady)
+	if condition == nil || condition.ObservedGeneration != generation {
+		return
+	}
+	switch {
+	case condition.Status == metav1.ConditionTrue:
+		c.ready++
+	case condition.Status == metav1.ConditionFalse && condition.Reason != conditionReasonInProgress:
+		c.failed++
+	}
+}
+
+// countMappings counts the HANAMappings and, if their controller runs, the
+// ClusterHANAMappings.
+func (r *HANAMappingOperatorReconciler) countMappings(ctx context.Context) (mappingCounts, error) {
+	counts := mappingCounts{}
+
+	hanaMappings := &hanav1.HANAMappingList{}
+	if err := r.Client.List(ctx, hanaMappings); err != nil {
+		return counts, err
+	}
+	for i := range hanaMappings.Items {
+		counts.add(hanaMappings.Items[i].Status.Conditions, hanaMappings.Items[i].Generation)
+	}
+
+	if r.ClusterHANAMappings {
+		clusterMappings := &hanav1.ClusterHANAMappingList{}
+		if err := r.Client.List(ctx, clusterMappings); err != nil {
+			return counts, err
+		}
+		for i := range clusterMappings.Items {
+			counts.add(clusterMappings.Items[i].Status.Conditions, clusterMappings.Items[i].Generation)
+		}
+	}
+	return counts, nil
+}
+
+// hanaMappingOperators enqueues all HANAMappingOperators, as each of them
+// aggregates the health of all HANAMappings.
+func (r *HANAMappingOperatorReconciler) hanaMappingOperators(ctx context.Context, _ client.Object) []reconcile.Request {
+	modules := &hanav1.HANAMappingOperatorList{}
+	if err := r.Client.List(ctx, modules); err != nil {
+		r.Log.Error(err, "failed to list hanamappingoperators")
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, module := range modules.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&module)})
+	}
+	return requests
+}
+
+func (r *HANAMappingOperatorReconciler) setState(ctx context.Context, module *hanav1.HANAMappingOperator, state string, status metav1.ConditionStatus, reason, message string, counts mappingCounts) error {
+	condition := metav1.Condition{
+		Type:               conditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: module.Generation,
+	}
+	return patchStatus(ctx, r.Client, module, func() {
+		meta.SetStatusCondition(&module.Status.Conditions, condition)
+		module.Status.State = state
+		module.Status.TotalMappings = int32(counts.total)
+		module.Status.ReadyMappings = int32(counts.ready)
+		module.Status.FailedMappings = int32(counts.failed)
+	})
+}
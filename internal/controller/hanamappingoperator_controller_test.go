@@ -0,0 +1,169 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+var _ = Describe("HANAMappingOperator Controller", func() {
+	const moduleName = "test-hanamappingoperator"
+
+	var (
+		ctx        context.Context
+		reconciler *HANAMappingOperatorReconciler
+		module     *hanav1.HANAMappingOperator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reconciler = &HANAMappingOperatorReconciler{
+			Client: k8sClient,
+			Log:    ctrl.Log.WithName("test-log"),
+			Scheme: k8sClient.Scheme(),
+			ClusterIDOptions: ClusterIDOptions{
+				DefaultBTPOperatorConfigmap: hanav1.NamespacedName{Namespace: testNamespace, Name: btpOperatorConfigmap},
+			},
+		}
+		module = &hanav1.HANAMappingOperator{ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: moduleName}}
+		Expect(k8sClient.Create(ctx, module)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(module), module); err == nil {
+			module.Finalizers = nil
+			Expect(k8sClient.Update(ctx, module)).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, module))).To(Succeed())
+		}
+	})
+
+	reconcileModule := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(module)})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should report a missing BTP operator config map", func() {
+		module.Spec.BTPOperatorConfigmap = &hanav1.NamespacedName{Namespace: testNamespace, Name: "missing"}
+		Expect(k8sClient.Update(ctx, module)).To(Succeed())
+
+		reconcileModule()
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(module), module)).To(Succeed())
+		Expect(module.Status.State).To(Equal(hanav1.StateError))
+		condition := meta.FindStatusCondition(module.Status.Conditions, conditionTypeReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal(conditionReasonPrerequisitesMissing))
+		Expect(condition.Message).To(ContainSubstring("missing"))
+		Expect(module.Finalizers).To(ContainElement(moduleFinalizerName))
+	})
+
+	It("should not require the BTP operator config map if the cluster ID is not read from it", func() {
+		module.Spec.BTPOperatorConfigmap = &hanav1.NamespacedName{Namespace: testNamespace, Name: "missing"}
+		Expect(k8sClient.Update(ctx, module)).To(Succeed())
+		reconciler.ClusterIDOptions.Source = ClusterIDSourceKubeSystem
+
+		reconcileModule()
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(module), module)).To(Succeed())
+		condition := meta.FindStatusCondition(module.Status.Conditions, conditionTypeReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Message).NotTo(ContainSubstring("config map"))
+	})
+
+	It("should require the CRDs of the enabled controllers", func() {
+		Expect(reconciler.requiredCustomResourceDefinitions()).NotTo(ContainElements(
+			"clusterhanamappings.hana.cloud.sap.com", "hanamappingsets.hana.cloud.sap.com", "serviceinstances.services.cloud.sap.com"))
+
+		reconciler.ClusterHANAMappings = true
+		reconciler.HANAMappingSets = true
+		Expect(reconciler.requiredCustomResourceDefinitions()).To(ContainElements(
+			"clusterhanamappings.hana.cloud.sap.com", "hanamappingsets.hana.cloud.sap.com", "serviceinstances.services.cloud.sap.com"))
+	})
+
+	It("should report missing CRDs of the BTP operator", func() {
+		reconcileModule()
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(module), module)).To(Succeed())
+		Expect(module.Status.State).To(Equal(hanav1.StateError))
+		condition := meta.FindStatusCondition(module.Status.Conditions, conditionTypeReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Message).To(ContainSubstring("services.cloud.sap.com"))
+	})
+
+	It("should block deletion until all hanamappings are deleted", func() {
+		reconcileModule()
+
+		hanamapping := newHANAMapping("test-module-hanamapping")
+		Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, module)).To(Succeed())
+		reconcileModule()
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(module), module)).To(Succeed())
+		Expect(module.Status.State).To(Equal(hanav1.StateDeleting))
+
+		Expect(k8sClient.Delete(ctx, hanamapping)).To(Succeed())
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(hanamapping), hanamapping))
+		}).Should(BeTrue())
+		reconcileModule()
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(module), module))).To(BeTrue())
+	})
+
+	It("should count ready and failed hanamappings", func() {
+		ready := hanav1.HANAMapping{Status: hanav1.HANAMappingStatus{Conditions: []metav1.Condition{
+			{Type: conditionTypeReady, Status: metav1.ConditionTrue, Reason: conditionReasonSucceeded},
+		}}}
+		inProgress := hanav1.HANAMapping{Status: hanav1.HANAMappingStatus{Conditions: []metav1.Condition{
+			{Type: conditionTypeReady, Status: metav1.ConditionFalse, Reason: conditionReasonInProgress},
+		}}}
+		failed := hanav1.HANAMapping{Status: hanav1.HANAMappingStatus{Conditions: []metav1.Condition{
+			{Type: conditionTypeReady, Status: metav1.ConditionFalse, Reason: conditionReasonInvalidCredentials},
+		}}}
+		counts := mappingCounts{}
+		for _, hanaMapping := range []hanav1.HANAMapping{ready, inProgress, failed, {}} {
+			counts.add(hanaMapping.Status.Conditions, hanaMapping.Generation)
+		}
+		Expect(counts).To(Equal(mappingCounts{total: 4, ready: 1, failed: 1}))
+	})
+
+	It("should count clusterhanamappings if their controller runs", func() {
+		clusterMapping := newClusterHANAMapping("test-module-clusterhanamapping")
+		Expect(k8sClient.Create(ctx, clusterMapping)).To(Succeed())
+		DeferCleanup(func() {
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, clusterMapping))).To(Succeed())
+		})
+
+		counts, err := reconciler.countMappings(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		total := counts.total
+
+		reconciler.ClusterHANAMappings = true
+		counts, err = reconciler.countMappings(ctx)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(counts.total).To(Equal(total + 1))
+	})
+})
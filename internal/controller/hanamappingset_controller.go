@@ -0,0 +1,412 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+const (
+	conditionReasonServiceInstanceNotReady = "ServiceInstanceNotReady"
+	conditionReasonInvalidGenerator        = "InvalidGenerator"
+
+	serviceInstanceRequeueInterval = 30 * time.Second
+)
+
+var serviceInstanceGVK = schema.GroupVersionKind{Group: "services.cloud.sap.com", Version: "v1", Kind: "ServiceInstance"}
+
+// HANAMappingSetReconciler reconciles a HANAMappingSet object
+type HANAMappingSetReconciler struct {
+	Client client.Client
+	Log    logr.Logger
+	Scheme *runtime.Scheme
+
+	MaxConcurrentReconciles int
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *HANAMappingSetReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&hanav1.HANAMappingSet{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Owns(&hanav1.HANAMapping{}).
+		Watches(&corev1.Namespace{}, handler.EnqueueRequestsFromMapFunc(r.hanaMappingSetsForNamespace),
+			builder.WithPredicates(predicate.LabelChangedPredicate{})).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingsets,verbs=get;list;watch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingsets/status,verbs=get;update;patch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingsets/finalizers,verbs=update
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=services.cloud.sap.com,resources=serviceinstances,verbs=get
+
+// Reconcile generates a HANAMapping for every target namespace and service
+// instance of a HANAMappingSet. Missing HANAMappings are created right away,
+// while outdated and surplus HANAMappings are updated and deleted only as long
+// as no more than maxUnavailable HANAMappings are not ready.
+func (r *HANAMappingSetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("hanamappingset", req.NamespacedName).WithValues("correlation_id", uuid.New().String())
+
+	mappingSet := &hanav1.HANAMappingSet{}
+	if err := r.Client.Get(ctx, req.NamespacedName, mappingSet); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+	if !mappingSet.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, nil
+	}
+	log.Info(fmt.Sprintf("got hanamappingset gen %d", mappingSet.Generation))
+	mappingSet = mappingSet.DeepCopy()
+
+	desired, unresolved, err := r.generate(ctx, mappingSet)
+	if err != nil {
+		if statusErr := r.setStatus(ctx, mappingSet, metav1.ConditionFalse, failureReason(err), err.Error()); statusErr != nil {
+			log.Error(statusErr, "failed to record generator error")
+		}
+		if _, ok := err.(*conditionError); ok {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	children := &hanav1.HANAMappingList{}
+	if err := r.Client.List(ctx, children, client.InNamespace(mappingSet.Namespace),
+		client.MatchingLabels{hanav1.MappingSetLabel: mappingSet.Name}); err != nil {
+		return ctrl.Result{}, err
+	}
+	existing := map[string]*hanav1.HANAMapping{}
+	unavailable := 0
+	for i := range children.Items {
+		child := &children.Items[i]
+		if !metav1.IsControlledBy(child, mappingSet) {
+			continue
+		}
+		existing[child.Name] = child
+		if !isHANAMappingReady(child) {
+			unavailable++
+		}
+	}
+
+	maxUnavailable, err := intstr.GetScaledValueFromIntOrPercent(mappingSet.Spec.Strategy.MaxUnavailable, len(desired), true)
+	if err != nil || mappingSet.Spec.Strategy.MaxUnavailable == nil || maxUnavailable < 1 {
+		maxUnavailable = 1
+	}
+
+	names := make([]string, 0, len(desired))
+	for name := range desired {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	updated, ready := 0, 0
+	for _, name := range names {
+		child, found := existing[name]
+		if !found {
+			if err := r.Client.Create(ctx, desired[name], fieldOwner); err != nil && !errors.IsAlreadyExists(err) {
+				return ctrl.Result{}, err
+			}
+			log.Info(fmt.Sprintf("created hanamapping %s", name))
+			updated++
+			continue
+		}
+		if isHANAMappingUpToDate(child, desired[name]) {
+			updated++
+			if isHANAMappingReady(child) {
+				ready++
+			}
+			continue
+		}
+		if unavailable >= maxUnavailable {
+			continue
+		}
+		if isHANAMappingReady(child) {
+			unavailable++
+		}
+		if err := patchObject(ctx, r.Client, child, func() { applyHANAMappingTemplate(child, desired[name]) }); err != nil {
+			return ctrl.Result{}, err
+		}
+		log.Info(fmt.Sprintf("updated hanamapping %s", name))
+		updated++
+	}
+
+	pending := make(map[string]bool, len(unresolved))
+	for _, serviceInstance := range unresolved {
+		pending[serviceInstance] = true
+	}
+
+	surplus := 0
+	for name, child := range existing {
+		if _, found := desired[name]; found {
+			continue
+		}
+		// the ServiceInstance may only be reprovisioned or restored, so its
+		// HANAMappings are kept until its instance ID is known again
+		if serviceInstance, found := child.Labels[hanav1.ServiceInstanceLabel]; found && pending[serviceInstance] {
+			continue
+		}
+		surplus++
+		if !child.DeletionTimestamp.IsZero() || unavailable >= maxUnavailable {
+			continue
+		}
+		if isHANAMappingReady(child) {
+			unavailable++
+		}
+		if err := r.Client.Delete(ctx, child); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.Info(fmt.Sprintf("deleted hanamapping %s", name))
+	}
+
+	mappingSet.Status.DesiredMappings = int32(len(desired))
+	mappingSet.Status.UpdatedMappings = int32(updated)
+	mappingSet.Status.ReadyMappings = int32(ready)
+
+	result := ctrl.Result{}
+	switch {
+	case len(unresolved) > 0:
+		result.RequeueAfter = serviceInstanceRequeueInterval
+		err = r.setStatus(ctx, mappingSet, metav1.ConditionFalse, conditionReasonServiceInstanceNotReady,
+			fmt.Sprintf("service instances %v have no instance ID yet", unresolved))
+	case ready < len(desired) || surplus > 0:
+		err = r.setStatus(ctx, mappingSet, metav1.ConditionFalse, conditionReasonInProgress,
+			fmt.Sprintf("%d of %d hanamappings are ready", ready, len(desired)))
+	default:
+		err = r.setStatus(ctx, mappingSet, metav1.ConditionTrue, conditionReasonSucceeded, "")
+	}
+	return result, err
+}
+
+// generate returns the desired HANAMappings by name and the names of the
+// referenced ServiceInstances which have no instance ID yet.
+func (r *HANAMappingSetReconciler) generate(ctx context.Context, mappingSet *hanav1.HANAMappingSet) (map[string]*hanav1.HANAMapping, []string, error) {
+	namespaces := map[string]bool{}
+	// the service instance IDs map to the ServiceInstance they were read from
+	serviceInstanceIDs := map[string]string{}
+	var unresolved []string
+
+	for _, generator := range mappingSet.Spec.Generators {
+		for _, namespace := range generator.Namespaces {
+			namespaces[namespace] = true
+		}
+		if generator.NamespaceSelector != nil {
+			selector, err := metav1.LabelSelectorAsSelector(generator.NamespaceSelector)
+			if err != nil {
+				return nil, nil, &conditionError{reason: conditionReasonInvalidGenerator, message: err.Error()}
+			}
+			namespaceList := &corev1.NamespaceList{}
+			if err := r.Client.List(ctx, namespaceList, client.MatchingLabelsSelector{Selector: selector}); err != nil {
+				return nil, nil, err
+			}
+			for _, namespace := range namespaceList.Items {
+				if namespace.DeletionTimestamp.IsZero() {
+					namespaces[namespace.Name] = true
+				}
+			}
+		}
+		for _, serviceInstance := range generator.ServiceInstances {
+			if len(serviceInstance.ID) > 0 {
+				if _, found := serviceInstanceIDs[serviceInstance.ID]; !found {
+					serviceInstanceIDs[serviceInstance.ID] = ""
+				}
+				continue
+			}
+			serviceInstanceID, err := r.getServiceInstanceID(ctx, mappingSet.Namespace, serviceInstance.Name)
+			if err != nil {
+				return nil, nil, err
+			}
+			if len(serviceInstanceID) == 0 {
+				unresolved = append(unresolved, serviceInstance.Name)
+				continue
+			}
+			serviceInstanceIDs[serviceInstanceID] = serviceInstance.Name
+		}
+	}
+
+	desired := map[string]*hanav1.HANAMapping{}
+	for namespace := range namespaces {
+		for serviceInstanceID, serviceInstanceName := range serviceInstanceIDs {
+			child, err := r.newHANAMapping(mappingSet, namespace, serviceInstanceID, serviceInstanceName)
+			if err != nil {
+				return nil, nil, err
+			}
+			desired[child.Name] = child
+		}
+	}
+	return desired, unresolved, nil
+}
+
+// getServiceInstanceID returns the instance ID of a ServiceInstance of the BTP
+// operator, which is empty until the instance is provisioned.
+func (r *HANAMappingSetReconciler) getServiceInstanceID(ctx context.Context, namespace, name string) (string, error) {
+	serviceInstance := &unstructured.Unstructured{}
+	serviceInstance.SetGroupVersionKind(serviceInstanceGVK)
+	if err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, serviceInstance); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	serviceInstanceID, _, err := unstructured.NestedString(serviceInstance.Object, "status", "instanceID")
+	return serviceInstanceID, err
+}
+
+func (r *HANAMappingSetReconciler) newHANAMapping(mappingSet *hanav1.HANAMappingSet, namespace, serviceInstanceID, serviceInstanceName string) (*hanav1.HANAMapping, error) {
+	hash := sha256.Sum256([]byte(namespace + "/" + serviceInstanceID))
+	template := mappingSet.Spec.Template
+
+	hanaMapping := &hanav1.HANAMapping{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   mappingSet.Namespace,
+			Name:        mappingSet.Name + "-" + hex.EncodeToString(hash[:])[:10],
+			Labels:      map[string]string{},
+			Annotations: map[string]string{},
+		},
+		Spec: hanav1.HANAMappingSpec{
+			BTPOperatorConfigmap: template.Spec.BTPOperatorConfigmap,
+			AdminCredentials:     template.Spec.AdminCredentials,
+			Mapping: hanav1.Mapping{
+				ServiceInstanceID: serviceInstanceID,
+				TargetNamespace:   namespace,
+				IsDefault:         template.Spec.IsDefault,
+			},
+			DeletionPolicy: template.Spec.DeletionPolicy,
+			ClusterID:      template.Spec.ClusterID,
+		},
+	}
+	if template.Spec.AdminAPIAccessSecret != nil {
+		secret := *template.Spec.AdminAPIAccessSecret
+		hanaMapping.Spec.AdminAPIAccessSecret = &secret
+	}
+	for key, value := range template.Metadata.Labels {
+		hanaMapping.Labels[key] = value
+	}
+	for key, value := range template.Metadata.Annotations {
+		hanaMapping.Annotations[key] = value
+	}
+	hanaMapping.Labels[hanav1.MappingSetLabel] = mappingSet.Name
+	if len(serviceInstanceName) > 0 {
+		hanaMapping.Labels[hanav1.ServiceInstanceLabel] = serviceInstanceName
+	}
+
+	if err := controllerutil.SetControllerReference(mappingSet, hanaMapping, r.Scheme); err != nil {
+		return nil, err
+	}
+	return hanaMapping, nil
+}
+
+// applyHANAMappingTemplate copies the spec, labels and annotations of desired
+// to hanaMapping, leaving labels and annotations set by others in place.
+func applyHANAMappingTemplate(hanaMapping, desired *hanav1.HANAMapping) {
+	hanaMapping.Spec = desired.Spec
+	if hanaMapping.Labels == nil {
+		hanaMapping.Labels = map[string]string{}
+	}
+	for key, value := range desired.Labels {
+		hanaMapping.Labels[key] = value
+	}
+	if hanaMapping.Annotations == nil {
+		hanaMapping.Annotations = map[string]string{}
+	}
+	for key, value := range desired.Annotations {
+		hanaMapping.Annotations[key] = value
+	}
+}
+
+func isHANAMappingUpToDate(hanaMapping, desired *hanav1.HANAMapping) bool {
+	updated := hanaMapping.DeepCopy()
+	applyHANAMappingTemplate(updated, desired)
+	return equality.Semantic.DeepEqual(hanaMapping.Spec, updated.Spec) &&
+		equality.Semantic.DeepEqual(hanaMapping.Labels, updated.Labels) &&
+		equality.Semantic.DeepEqual(hanaMapping.Annotations, updated.Annotations)
+}
+
+// isHANAMappingReady is true if the HANAMapping is ready at its current generation.
+func isHANAMappingReady(hanaMapping *hanav1.HANAMapping) bool {
+	condition := meta.FindStatusCondition(hanaMapping.Status.Conditions, conditionTypeReady)
+	return condition != nil && condition.Status == metav1.ConditionTrue &&
+		condition.ObservedGeneration == hanaMapping.Generation
+}
+
+// hanaMappingSetsForNamespace enqueues the HANAMappingSets selecting namespaces
+// by labels, as the namespace may have started or stopped to match.
+func (r *HANAMappingSetReconciler) hanaMappingSetsForNamespace(ctx context.Context, namespace client.Object) []reconcile.Request {
+	mappingSets := &hanav1.HANAMappingSetList{}
+	if err := r.Client.List(ctx, mappingSets); err != nil {
+		r.Log.Error(err, "failed to list hanamappingsets", "namespace", namespace.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, mappingSet := range mappingSets.Items {
+		for _, generator := range mappingSet.Spec.Generators {
+			if generator.NamespaceSelector != nil {
+				requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&mappingSet)})
+				break
+			}
+		}
+	}
+	return requests
+}
+
+func (r *HANAMappingSetReconciler) setStatus(ctx context.Context, mappingSet *hanav1.HANAMappingSet, status metav1.ConditionStatus, reason, message string) error {
+	condition := metav1.Condition{
+		Type:               conditionTypeReady,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: mappingSet.Generation,
+	}
+	desired, updated, ready := mappingSet.Status.DesiredMappings, mappingSet.Status.UpdatedMappings, mappingSet.Status.ReadyMappings
+	return patchStatus(ctx, r.Client, mappingSet, func() {
+		meta.SetStatusCondition(&mappingSet.Status.Conditions, condition)
+		mappingSet.Status.DesiredMappings = desired
+		mappingSet.Status.UpdatedMappings = updated
+		mappingSet.Status.ReadyMappings = ready
+	})
+}
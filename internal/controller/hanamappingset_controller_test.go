@@ -0,0 +1,295 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+var _ = Describe("HANAMappingSet Controller", func() {
+	const (
+		secondServiceInstanceID = "5a9d5a1c-5c37-4c2e-9d1a-3f0d8f6a6e21"
+	)
+
+	var (
+		log        logr.Logger
+		ctx        context.Context
+		reconciler *HANAMappingSetReconciler
+	)
+
+	BeforeEach(func() {
+		log = ctrl.Log.WithName("test-log")
+		ctx = context.Background()
+		reconciler = &HANAMappingSetReconciler{
+			Client: k8sClient,
+			Log:    log,
+			Scheme: k8sClient.Scheme(),
+		}
+	})
+
+	newHANAMappingSet := func(name string, namespaces ...string) *hanav1.HANAMappingSet {
+		return &hanav1.HANAMappingSet{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: name},
+			Spec: hanav1.HANAMappingSetSpec{
+				Generators: []hanav1.HANAMappingSetGenerator{
+					{Namespaces: namespaces},
+					{ServiceInstances: []hanav1.ServiceInstanceReference{{ID: hanamappingServiceInstanceID}}},
+				},
+				Template: hanav1.HANAMappingTemplate{
+					Metadata: hanav1.HANAMappingTemplateMetadata{Labels: map[string]string{"app": "test"}},
+					Spec: hanav1.HANAMappingTemplateSpec{
+						AdminAPIAccessSecret: &hanav1.NamespacedName{Namespace: testNamespace, Name: adminAPIAccessSecret},
+					},
+				},
+			},
+		}
+	}
+
+	reconcileHANAMappingSet := func(mappingSet *hanav1.HANAMappingSet) {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(mappingSet)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(mappingSet), mappingSet)).To(Succeed())
+	}
+
+	listChildren := func(mappingSet *hanav1.HANAMappingSet) []hanav1.HANAMapping {
+		children := &hanav1.HANAMappingList{}
+		Expect(k8sClient.List(ctx, children, client.InNamespace(testNamespace),
+			client.MatchingLabels{hanav1.MappingSetLabel: mappingSet.Name})).To(Succeed())
+		return children.Items
+	}
+
+	setReady := func(children []hanav1.HANAMapping) {
+		for i := range children {
+			child := &children[i]
+			meta.SetStatusCondition(&child.Status.Conditions, metav1.Condition{
+				Type:               conditionTypeReady,
+				Status:             metav1.ConditionTrue,
+				Reason:             conditionReasonSucceeded,
+				ObservedGeneration: child.Generation,
+			})
+			Expect(k8sClient.Status().Update(ctx, child)).To(Succeed())
+		}
+	}
+
+	deleteHANAMappingSet := func(mappingSet *hanav1.HANAMappingSet) {
+		Expect(k8sClient.Delete(ctx, mappingSet)).To(Succeed())
+		Expect(k8sClient.DeleteAllOf(ctx, &hanav1.HANAMapping{}, client.InNamespace(testNamespace),
+			client.MatchingLabels{hanav1.MappingSetLabel: mappingSet.Name})).To(Succeed())
+	}
+
+	It("should generate a hanamapping per namespace and service instance", func() {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:   "mappingset-selected",
+			Labels: map[string]string{"hana.cloud.sap.com/tenant": "true"},
+		}}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+
+		mappingSet := newHANAMappingSet("generated-hanamappingset", "mappingset-a", "mappingset-b")
+		mappingSet.Spec.Generators = append(mappingSet.Spec.Generators,
+			hanav1.HANAMappingSetGenerator{NamespaceSelector: &metav1.LabelSelector{MatchLabels: namespace.Labels}},
+			hanav1.HANAMappingSetGenerator{ServiceInstances: []hanav1.ServiceInstanceReference{{ID: secondServiceInstanceID}}})
+		Expect(k8sClient.Create(ctx, mappingSet)).To(Succeed())
+		defer deleteHANAMappingSet(mappingSet)
+
+		reconcileHANAMappingSet(mappingSet)
+
+		children := listChildren(mappingSet)
+		Expect(children).To(HaveLen(6))
+		targets := map[string]bool{}
+		for _, child := range children {
+			Expect(metav1.IsControlledBy(&child, mappingSet)).To(BeTrue())
+			Expect(child.Labels).To(HaveKeyWithValue("app", "test"))
+			Expect(child.Spec.AdminAPIAccessSecret.Name).To(Equal(adminAPIAccessSecret))
+			targets[child.Spec.Mapping.TargetNamespace+"/"+child.Spec.Mapping.ServiceInstanceID] = true
+		}
+		for _, target := range []string{"mappingset-a", "mappingset-b", "mappingset-selected"} {
+			Expect(targets).To(HaveKey(target + "/" + hanamappingServiceInstanceID))
+			Expect(targets).To(HaveKey(target + "/" + secondServiceInstanceID))
+		}
+
+		Expect(mappingSet.Status.DesiredMappings).To(Equal(int32(6)))
+		Expect(mappingSet.Status.UpdatedMappings).To(Equal(int32(6)))
+		Expect(mappingSet.Status.ReadyMappings).To(Equal(int32(0)))
+		Expect(meta.IsStatusConditionFalse(mappingSet.Status.Conditions, conditionTypeReady)).To(BeTrue())
+
+		setReady(listChildren(mappingSet))
+		reconcileHANAMappingSet(mappingSet)
+		Expect(mappingSet.Status.ReadyMappings).To(Equal(int32(6)))
+		Expect(meta.IsStatusConditionTrue(mappingSet.Status.Conditions, conditionTypeReady)).To(BeTrue())
+	})
+
+	It("should roll out template changes within maxUnavailable", func() {
+		mappingSet := newHANAMappingSet("rolling-hanamappingset", "rolling-a", "rolling-b", "rolling-c")
+		Expect(k8sClient.Create(ctx, mappingSet)).To(Succeed())
+		defer deleteHANAMappingSet(mappingSet)
+
+		reconcileHANAMappingSet(mappingSet)
+		setReady(listChildren(mappingSet))
+
+		mappingSet.Spec.Template.Spec.DeletionPolicy = "Orphan"
+		Expect(k8sClient.Update(ctx, mappingSet)).To(Succeed())
+
+		orphaned := func() []hanav1.HANAMapping {
+			var children []hanav1.HANAMapping
+			for _, child := range listChildren(mappingSet) {
+				if child.Spec.DeletionPolicy == "Orphan" {
+					children = append(children, child)
+				}
+			}
+			return children
+		}
+
+		reconcileHANAMappingSet(mappingSet)
+		Expect(orphaned()).To(HaveLen(1))
+		Expect(mappingSet.Status.UpdatedMappings).To(Equal(int32(1)))
+
+		By("waiting for the updated hanamapping to become ready")
+		reconcileHANAMappingSet(mappingSet)
+		Expect(orphaned()).To(HaveLen(1))
+
+		setReady(orphaned())
+		reconcileHANAMappingSet(mappingSet)
+		Expect(orphaned()).To(HaveLen(2))
+
+		By("allowing all hanamappings to be unavailable")
+		maxUnavailable := intstr.FromString("100%")
+		mappingSet.Spec.Strategy.MaxUnavailable = &maxUnavailable
+		Expect(k8sClient.Update(ctx, mappingSet)).To(Succeed())
+		reconcileHANAMappingSet(mappingSet)
+		Expect(orphaned()).To(HaveLen(3))
+	})
+
+	It("should delete hanamappings which are no longer generated", func() {
+		mappingSet := newHANAMappingSet("shrinking-hanamappingset", "shrinking-a", "shrinking-b")
+		Expect(k8sClient.Create(ctx, mappingSet)).To(Succeed())
+		defer deleteHANAMappingSet(mappingSet)
+
+		reconcileHANAMappingSet(mappingSet)
+		setReady(listChildren(mappingSet))
+
+		mappingSet.Spec.Generators[0].Namespaces = []string{"shrinking-a"}
+		Expect(k8sClient.Update(ctx, mappingSet)).To(Succeed())
+		reconcileHANAMappingSet(mappingSet)
+
+		children := listChildren(mappingSet)
+		Expect(children).To(HaveLen(1))
+		Expect(children[0].Spec.Mapping.TargetNamespace).To(Equal("shrinking-a"))
+		Expect(mappingSet.Status.DesiredMappings).To(Equal(int32(1)))
+	})
+
+	It("should not adopt hanamappings controlled by others", func() {
+		mappingSet := newHANAMappingSet("foreign-hanamappingset", "foreign-a")
+		Expect(k8sClient.Create(ctx, mappingSet)).To(Succeed())
+		defer deleteHANAMappingSet(mappingSet)
+
+		foreign := newHANAMapping("foreign-hanamapping")
+		foreign.Labels = map[string]string{hanav1.MappingSetLabel: mappingSet.Name}
+		Expect(k8sClient.Create(ctx, foreign)).To(Succeed())
+
+		reconcileHANAMappingSet(mappingSet)
+		Expect(listChildren(mappingSet)).To(HaveLen(2))
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: foreign.Name}, foreign)).To(Succeed())
+	})
+
+	It("should keep the hanamappings of a service instance without instance ID", func() {
+		serviceInstances := &serviceInstanceClient{
+			Client:      k8sClient,
+			instanceIDs: map[string]string{"mappingset-instance": secondServiceInstanceID},
+		}
+		reconciler.Client = serviceInstances
+
+		mappingSet := newHANAMappingSet("unresolved-hanamappingset", "unresolved-a")
+		mappingSet.Spec.Generators = append(mappingSet.Spec.Generators,
+			hanav1.HANAMappingSetGenerator{ServiceInstances: []hanav1.ServiceInstanceReference{{Name: "mappingset-instance"}}})
+		Expect(k8sClient.Create(ctx, mappingSet)).To(Succeed())
+		defer deleteHANAMappingSet(mappingSet)
+
+		reconcileHANAMappingSet(mappingSet)
+		children := listChildren(mappingSet)
+		Expect(children).To(HaveLen(2))
+		labelled := 0
+		for _, child := range children {
+			if child.Spec.Mapping.ServiceInstanceID == secondServiceInstanceID {
+				Expect(child.Labels).To(HaveKeyWithValue(hanav1.ServiceInstanceLabel, "mappingset-instance"))
+				labelled++
+			} else {
+				Expect(child.Labels).NotTo(HaveKey(hanav1.ServiceInstanceLabel))
+			}
+		}
+		Expect(labelled).To(Equal(1))
+		setReady(children)
+
+		By("recreating the service instance")
+		serviceInstances.instanceIDs["mappingset-instance"] = ""
+		reconcileHANAMappingSet(mappingSet)
+		Expect(listChildren(mappingSet)).To(HaveLen(2))
+		Expect(meta.FindStatusCondition(mappingSet.Status.Conditions, conditionTypeReady).Reason).To(Equal(conditionReasonServiceInstanceNotReady))
+
+		delete(serviceInstances.instanceIDs, "mappingset-instance")
+		reconcileHANAMappingSet(mappingSet)
+		Expect(listChildren(mappingSet)).To(HaveLen(2))
+
+		By("dropping the service instance")
+		mappingSet.Spec.Generators = mappingSet.Spec.Generators[:2]
+		Expect(k8sClient.Update(ctx, mappingSet)).To(Succeed())
+		reconcileHANAMappingSet(mappingSet)
+		children = listChildren(mappingSet)
+		Expect(children).To(HaveLen(1))
+		Expect(children[0].Spec.Mapping.ServiceInstanceID).To(Equal(hanamappingServiceInstanceID))
+	})
+})
+
+// serviceInstanceClient serves ServiceInstances of the BTP operator, whose CRD
+// is not installed in the test environment, with the given instance IDs.
+type serviceInstanceClient struct {
+	client.Client
+	instanceIDs map[string]string
+}
+
+func (c *serviceInstanceClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	serviceInstance, ok := obj.(*unstructured.Unstructured)
+	if !ok || serviceInstance.GroupVersionKind() != serviceInstanceGVK {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+	instanceID, found := c.instanceIDs[key.Name]
+	if !found {
+		return errors.NewNotFound(schema.GroupResource{Group: serviceInstanceGVK.Group, Resource: "serviceinstances"}, key.Name)
+	}
+	serviceInstance.SetNamespace(key.Namespace)
+	serviceInstance.SetName(key.Name)
+	return unstructured.SetNestedField(serviceInstance.Object, instanceID, "status", "instanceID")
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+// DesiredMappingID returns the mapping ID the HANAMappingReconciler creates
+// for hanaMapping when it resolves the cluster ID with opts.
+func DesiredMappingID(ctx context.Context, c client.Client, opts ClusterIDOptions, hanaMapping *hanav1.HANAMapping) (*hanav1.MappingID, error) {
+	r := &HANAMappingReconciler{Client: c, ClusterIDOptions: opts}
+	return r.getDesiredMappingID(ctx, hanaMapping)
+}
+
+// AdminAPIAccessBinding returns the admin API access binding the
+// HANAMappingReconciler uses for hanaMapping.
+func AdminAPIAccessBinding(ctx context.Context, c client.Client, hanaMapping *hanav1.HANAMapping) (inventory.Binding, error) {
+	return resolveAdminAPIAccessBinding(ctx, c, nil, hanaMapping.Namespace, hanaMapping.Spec.AdminAPIAccessSecret, hanaMapping.Spec.AdminCredentials)
+}
+
+// NormalizeMappingID defaults the platform of a recorded mapping ID.
+func NormalizeMappingID(mappingID *hanav1.MappingID) *hanav1.MappingID {
+	return normalizeMappingID(mappingID)
+}
+
+// MappingOwners returns the HANAMappings and ClusterHANAMappings by the mapping
+// IDs of the service instance they own, keyed like InventoryMappingID.
+func MappingOwners(ctx context.Context, c client.Reader, serviceInstanceID string) (map[hanav1.MappingID]string, error) {
+	return getMappingOwners(ctx, c, serviceInstanceID)
+}
+
+// InventoryMappingID returns the normalized mapping ID of an inventory mapping.
+func InventoryMappingID(serviceInstanceID string, mapping inventory.Mapping) hanav1.MappingID {
+	return inventoryMappingID(serviceInstanceID, mapping)
+}
+
+// SecretAdminAPIAccessBinding reads the admin API access binding from a secret.
+func SecretAdminAPIAccessBinding(ctx context.Context, c client.Client, secretName types.NamespacedName) (inventory.Binding, error) {
+	return getAdminAPIAccessBinding(ctx, c, secretName)
+}
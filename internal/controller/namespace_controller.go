@@ -0,0 +1,236 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"github.com/google/uuid"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+const (
+	// NamespaceHANAMappingName is the name of the HANAMapping created for an
+	// annotated namespace.
+	NamespaceHANAMappingName = "namespace-hanamapping"
+
+	eventReasonHANAMappingConflict = "HANAMappingConflict"
+	eventReasonHANAMappingRejected = "HANAMappingRejected"
+	eventReasonInvalidAnnotation   = "InvalidAnnotation"
+)
+
+// serviceInstanceIDPattern matches the GUIDs the HANAMapping validation
+// accepts as service instance ID.
+var serviceInstanceIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// NamespaceReconciler creates a HANAMapping in every namespace annotated with
+// a service instance ID and deletes it when the annotation is removed. The
+// HANAMapping is controlled by the namespace.
+type NamespaceReconciler struct {
+	Client   client.Client
+	Log      logr.Logger
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// DefaultAdminAPIAccessSecret is referenced by HANAMappings of namespaces
+	// which do not annotate credentials. The webhook only admits them if a
+	// HANAMappingCredentialGrant in the namespace of the secret permits the
+	// annotated namespaces.
+	DefaultAdminAPIAccessSecret *hanav1.NamespacedName
+	// WatchNamespaces restricts the reconciler to the given namespaces, all
+	// namespaces are reconciled if it is empty.
+	WatchNamespaces         []string
+	MaxConcurrentReconciles int
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *NamespaceReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("namespace").
+		For(&corev1.Namespace{}, builder.WithPredicates(predicate.NewPredicateFuncs(r.watches),
+			annotationChangedPredicate(hanav1.ServiceInstanceIDAnnotation, hanav1.AdminCredentialsAnnotation, hanav1.AdminAPIAccessSecretAnnotation))).
+		Owns(&hanav1.HANAMapping{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Watches(&hanav1.HANAMappingCredentialGrant{}, handler.EnqueueRequestsFromMapFunc(r.namespacesForCredentialGrant)).
+		WithOptions(controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}).
+		Complete(r)
+}
+
+//+kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappings,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=hana.cloud.sap.com,resources=hanamappingcredentialgrants,verbs=get;list;watch
+
+// Reconcile creates, updates or deletes the HANAMapping of a namespace
+// according to its annotations.
+func (r *NamespaceReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := r.Log.WithValues("namespace", req.Name).WithValues("correlation_id", uuid.New().String())
+
+	namespace := &corev1.Namespace{}
+	if err := r.Client.Get(ctx, req.NamespacedName, namespace); err != nil {
+		if !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	hanaMapping := &hanav1.HANAMapping{}
+	err := r.Client.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: NamespaceHANAMappingName}, hanaMapping)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(hanaMapping, namespace) {
+		r.event(namespace, corev1.EventTypeWarning, eventReasonHANAMappingConflict,
+			fmt.Sprintf("hanamapping %s exists already and is not controlled by the namespace", NamespaceHANAMappingName))
+		return ctrl.Result{}, nil
+	}
+
+	serviceInstanceID := namespace.Annotations[hanav1.ServiceInstanceIDAnnotation]
+	if len(serviceInstanceID) == 0 || !namespace.DeletionTimestamp.IsZero() {
+		if !exists {
+			return ctrl.Result{}, nil
+		}
+		if err := r.Client.Delete(ctx, hanaMapping); err != nil && !errors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+		log.Info("deleted hanamapping")
+		return ctrl.Result{}, nil
+	}
+
+	if !serviceInstanceIDPattern.MatchString(serviceInstanceID) {
+		r.event(namespace, corev1.EventTypeWarning, eventReasonInvalidAnnotation,
+			fmt.Sprintf("annotation %s must be a GUID", hanav1.ServiceInstanceIDAnnotation))
+		return ctrl.Result{}, nil
+	}
+
+	adminAPIAccessSecret, adminCredentials, err := r.credentials(namespace)
+	if err != nil {
+		r.event(namespace, corev1.EventTypeWarning, eventReasonInvalidAnnotation, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	if !exists {
+		hanaMapping = &hanav1.HANAMapping{
+			ObjectMeta: metav1.ObjectMeta{Namespace: namespace.Name, Name: NamespaceHANAMappingName},
+		}
+	}
+	result, err := controllerutil.CreateOrPatch(ctx, r.Client, hanaMapping, func() error {
+		hanaMapping.Spec.AdminAPIAccessSecret = adminAPIAccessSecret
+		hanaMapping.Spec.AdminCredentials = adminCredentials
+		hanaMapping.Spec.AllowRemap = true
+		hanaMapping.Spec.Mapping.ServiceInstanceID = serviceInstanceID
+		hanaMapping.Spec.Mapping.TargetNamespace = namespace.Name
+		return controllerutil.SetControllerReference(namespace, hanaMapping, r.Scheme)
+	})
+	if errors.IsForbidden(err) || errors.IsInvalid(err) {
+		// Retrying does not help until the namespace or a credential grant
+		// permitting it changes, both trigger a reconciliation.
+		r.event(namespace, corev1.EventTypeWarning, eventReasonHANAMappingRejected,
+			fmt.Sprintf("hanamapping %s was rejected: %s", NamespaceHANAMappingName, err))
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if result != controllerutil.OperationResultNone {
+		log.Info(fmt.Sprintf("%s hanamapping", result))
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// credentials returns the credentials reference of the namespace annotations,
+// falling back to DefaultAdminAPIAccessSecret.
+func (r *NamespaceReconciler) credentials(namespace *corev1.Namespace) (*hanav1.NamespacedName, string, error) {
+	adminCredentials := namespace.Annotations[hanav1.AdminCredentialsAnnotation]
+	secret, hasSecret := namespace.Annotations[hanav1.AdminAPIAccessSecretAnnotation]
+
+	switch {
+	case len(adminCredentials) > 0 && hasSecret:
+		return nil, "", fmt.Errorf("only one of the annotations %s and %s may be set", hanav1.AdminCredentialsAnnotation, hanav1.AdminAPIAccessSecretAnnotation)
+	case len(adminCredentials) > 0:
+		return nil, adminCredentials, nil
+	case hasSecret:
+		secretNamespace, secretName, ok := strings.Cut(secret, "/")
+		if !ok || len(secretNamespace) == 0 || len(secretName) == 0 {
+			return nil, "", fmt.Errorf("annotation %s must reference a secret as namespace/name", hanav1.AdminAPIAccessSecretAnnotation)
+		}
+		return &hanav1.NamespacedName{Namespace: secretNamespace, Name: secretName}, "", nil
+	case r.DefaultAdminAPIAccessSecret != nil:
+		secret := *r.DefaultAdminAPIAccessSecret
+		return &secret, "", nil
+	default:
+		return nil, "", fmt.Errorf("one of the annotations %s and %s must be set", hanav1.AdminCredentialsAnnotation, hanav1.AdminAPIAccessSecretAnnotation)
+	}
+}
+
+func (r *NamespaceReconciler) event(namespace *corev1.Namespace, eventType, reason, message string) {
+	r.Log.Info(message, "namespace", namespace.Name, "reason", reason)
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Event(namespace, eventType, reason, message)
+}
+
+// namespacesForCredentialGrant enqueues the namespaces a changed
+// HANAMappingCredentialGrant permits, so that HANAMappings which were rejected
+// for lack of a grant are created.
+func (r *NamespaceReconciler) namespacesForCredentialGrant(ctx context.Context, obj client.Object) []reconcile.Request {
+	grant, ok := obj.(*hanav1.HANAMappingCredentialGrant)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, from := range grant.Spec.From {
+		if r.watches(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: from.Namespace}}) {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: from.Namespace}})
+		}
+	}
+	return requests
+}
+
+func (r *NamespaceReconciler) watches(namespace client.Object) bool {
+	if len(r.WatchNamespaces) == 0 {
+		return true
+	}
+	for _, watched := range r.WatchNamespaces {
+		if watched == namespace.GetName() {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+)
+
+var _ = Describe("Namespace Controller", func() {
+	var (
+		log        logr.Logger
+		ctx        context.Context
+		recorder   *record.FakeRecorder
+		reconciler *NamespaceReconciler
+	)
+
+	BeforeEach(func() {
+		log = ctrl.Log.WithName("test-log")
+		ctx = context.Background()
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &NamespaceReconciler{
+			Client:   k8sClient,
+			Log:      log,
+			Scheme:   k8sClient.Scheme(),
+			Recorder: recorder,
+		}
+	})
+
+	AfterEach(func() {
+		hanamappings := &hanav1.HANAMappingList{}
+		Expect(k8sClient.List(ctx, hanamappings)).To(Succeed())
+		for i := range hanamappings.Items {
+			if hanamappings.Items[i].Name == NamespaceHANAMappingName {
+				Expect(k8sClient.Delete(ctx, &hanamappings.Items[i])).To(Succeed())
+			}
+		}
+	})
+
+	createNamespace := func(name string, annotations map[string]string) *corev1.Namespace {
+		namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Annotations: annotations}}
+		Expect(k8sClient.Create(ctx, namespace)).To(Succeed())
+		return namespace
+	}
+
+	reconcileNamespace := func(name string) {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: name}})
+		Expect(err).NotTo(HaveOccurred())
+	}
+
+	It("should create and delete the hanamapping of an annotated namespace", func() {
+		namespace := createNamespace("test-annotated-namespace", map[string]string{
+			hanav1.ServiceInstanceIDAnnotation: hanamappingServiceInstanceID,
+			hanav1.AdminCredentialsAnnotation:  "test-admincredentials",
+		})
+
+		reconcileNamespace(namespace.Name)
+
+		hanamapping := &hanav1.HANAMapping{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: NamespaceHANAMappingName}, hanamapping)).To(Succeed())
+		Expect(metav1.IsControlledBy(hanamapping, namespace)).To(BeTrue())
+		Expect(hanamapping.Spec.AdminCredentials).To(Equal("test-admincredentials"))
+		Expect(hanamapping.Spec.Mapping.ServiceInstanceID).To(Equal(hanamappingServiceInstanceID))
+		Expect(hanamapping.Spec.Mapping.TargetNamespace).To(Equal(namespace.Name))
+
+		delete(namespace.Annotations, hanav1.ServiceInstanceIDAnnotation)
+		Expect(k8sClient.Update(ctx, namespace)).To(Succeed())
+
+		reconcileNamespace(namespace.Name)
+
+		err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: NamespaceHANAMappingName}, hanamapping)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should reference the default admin api access secret", func() {
+		reconciler.DefaultAdminAPIAccessSecret = &hanav1.NamespacedName{Namespace: testNamespace, Name: adminAPIAccessSecret}
+		namespace := createNamespace("test-default-secret-namespace", map[string]string{
+			hanav1.ServiceInstanceIDAnnotation: hanamappingServiceInstanceID,
+		})
+
+		reconcileNamespace(namespace.Name)
+
+		hanamapping := &hanav1.HANAMapping{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: NamespaceHANAMappingName}, hanamapping)).To(Succeed())
+		Expect(*hanamapping.Spec.AdminAPIAccessSecret).To(Equal(*reconciler.DefaultAdminAPIAccessSecret))
+	})
+
+	It("should not take over a hanamapping which is not controlled by the namespace", func() {
+		namespace := createNamespace("test-conflicting-namespace", map[string]string{
+			hanav1.ServiceInstanceIDAnnotation: hanamappingServiceInstanceID,
+			hanav1.AdminCredentialsAnnotation:  "test-admincredentials",
+		})
+		hanamapping := newHANAMapping(NamespaceHANAMappingName)
+		hanamapping.Namespace = namespace.Name
+		Expect(k8sClient.Create(ctx, hanamapping)).To(Succeed())
+
+		reconcileNamespace(namespace.Name)
+
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonHANAMappingConflict)))
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: NamespaceHANAMappingName}, hanamapping)).To(Succeed())
+		Expect(hanamapping.OwnerReferences).To(BeEmpty())
+	})
+
+	It("should report a service instance ID which is not a GUID", func() {
+		namespace := createNamespace("test-invalid-instance-namespace", map[string]string{
+			hanav1.ServiceInstanceIDAnnotation: "not-a-guid",
+			hanav1.AdminCredentialsAnnotation:  "test-admincredentials",
+		})
+
+		reconcileNamespace(namespace.Name)
+
+		Expect(recorder.Events).To(Receive(ContainSubstring(eventReasonInvalidAnnotation)))
+		err := k8sClient.Get(ctx, types.NamespacedName{Namespace: namespace.Name, Name: NamespaceHANAMappingName}, &hanav1.HANAMapping{})
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should enqueue the watched namespaces a credential grant permits", func() {
+		reconciler.WatchNamespaces = []string{"team-a", "team-b"}
+		grant := &hanav1.HANAMappingCredentialGrant{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: "test-grant"},
+			Spec: hanav1.HANAMappingCredentialGrantSpec{
+				From: []hanav1.CredentialGrantFrom{{Namespace: "team-a"}, {Namespace: "team-c"}},
+			},
+		}
+
+		Expect(reconciler.namespacesForCredentialGrant(ctx, grant)).To(Equal([]reconcile.Request{
+			{NamespacedName: types.NamespacedName{Name: "team-a"}},
+		}))
+	})
+
+	It("should only watch the configured namespaces", func() {
+		Expect(reconciler.watches(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})).To(BeTrue())
+
+		reconciler.WatchNamespaces = []string{"team-b"}
+		Expect(reconciler.watches(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a"}})).To(BeFalse())
+		Expect(reconciler.watches(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-b"}})).To(BeTrue())
+	})
+})
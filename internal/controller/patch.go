@@ -0,0 +1,70 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fieldOwner is the field manager of all writes of the operator.
+const fieldOwner = client.FieldOwner("hana-mapping-operator")
+
+// patchObject applies mutate to obj and merge patches the changes. The patch
+// carries the resource version of obj, so that concurrent edits are not
+// overwritten. On conflict obj is fetched again from the API server, as the
+// cache may not have caught up with the conflicting write yet, and mutate is
+// reapplied.
+func patchObject[T client.Object](ctx context.Context, c client.Client, obj T, mutate func()) error {
+	return patchOnConflict(ctx, c, obj, mutate, func(patch client.Patch) error {
+		return c.Patch(ctx, obj, patch, fieldOwner)
+	})
+}
+
+// patchStatus is like patchObject for the status subresource.
+func patchStatus[T client.Object](ctx context.Context, c client.Client, obj T, mutate func()) error {
+	return patchOnConflict(ctx, c, obj, mutate, func(patch client.Patch) error {
+		return c.Status().Patch(ctx, obj, patch, fieldOwner)
+	})
+}
+
+// apiReader is implemented by clients which can bypass their cache.
+type apiReader interface {
+	APIReader() client.Reader
+}
+
+func patchOnConflict[T client.Object](ctx context.Context, c client.Client, obj T, mutate func(), patch func(client.Patch) error) error {
+	var reader client.Reader = c
+	if r, ok := c.(apiReader); ok {
+		reader = r.APIReader()
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		base := obj.DeepCopyObject().(T)
+		mutate()
+		err := patch(client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+		if errors.IsConflict(err) {
+			if getErr := reader.Get(ctx, client.ObjectKeyFromObject(obj), obj); getErr != nil {
+				return getErr
+			}
+		}
+		return err
+	})
+}
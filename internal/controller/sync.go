@@ -0,0 +1,287 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	hanav1 "github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/api/v1"
+	"github.com/SAP-samples/hana-cloud-instance-mapping-operator-for-kyma/internal/inventory"
+)
+
+const (
+	defaultBTPOperatorConfigmapNamespace = "kyma-system"
+	defaultBTPOperatorConfigmapName      = "sap-btp-operator-config"
+
+	conditionTypeReady = "Ready"
+
+	conditionReasonInProgress = "InProgress"
+	conditionReasonSucceeded  = "Succeeded"
+	conditionReasonFailed     = "Failed"
+
+	conditionReasonInventoryUnavailable    = "InventoryUnavailable"
+	conditionReasonInvalidCredentials      = "InvalidCredentials"
+	conditionReasonCredentialsNotPermitted = "CredentialsNotPermitted"
+	conditionReasonCredentialsNotReady     = "CredentialsNotReady"
+
+	inventoryUnavailableRequeueInterval = 30 * time.Second
+)
+
+var serviceBindingGVK = schema.GroupVersionKind{Group: "services.cloud.sap.com", Version: "v1", Kind: "ServiceBinding"}
+
+// createInventoryMapping creates the mapping unless it already exists. An
+// existing mapping is only accepted if it is owned by the caller already,
+// it is updated if its isDefault flag differs from the desired one.
+func createInventoryMapping(ctx context.Context, inventoryClient inventory.Client, mappingID *hanav1.MappingID, isDefault bool, owned bool) error {
+	mapping := inventory.Mapping{
+		Platform:    mappingID.Platform,
+		PrimaryID:   mappingID.PrimaryID,
+		SecondaryID: mappingID.SecondaryID,
+		IsDefault:   isDefault,
+	}
+	return inventory.EnsureMapping(ctx, inventoryClient, mappingID.ServiceInstanceID, mapping, owned)
+}
+
+// deleteInventoryMapping deletes the mapping if it still exists.
+func deleteInventoryMapping(ctx context.Context, inventoryClient inventory.Client, mappingID *hanav1.MappingID) error {
+	return inventory.RemoveMapping(ctx, inventoryClient, mappingID.ServiceInstanceID, mappingID.Platform, mappingID.PrimaryID, mappingID.SecondaryID)
+}
+
+// ensureInventoryMappingNotExists fails with ErrMappingAlreadyExists if the
+// mapping exists, so that a foreign mapping is not journaled and taken over.
+func ensureInventoryMappingNotExists(ctx context.Context, inventoryClient inventory.Client, mappingID *hanav1.MappingID) error {
+	_, err := inventoryClient.GetMapping(ctx, mappingID.ServiceInstanceID, mappingID.Platform, mappingID.PrimaryID, mappingID.SecondaryID)
+	if err == nil {
+		return inventory.ErrMappingAlreadyExists
+	}
+	if err != inventory.ErrMappingNotFound {
+		return err
+	}
+	return nil
+}
+
+// replayOperations deletes the mappings journaled in operations, which point
+// into the status of obj, unless keep retains them. Every deletion is dropped
+// from the journal by a status patch, together with what forget clears for
+// the mapping.
+func replayOperations(ctx context.Context, c client.Client, obj client.Object, operations *[]hanav1.InventoryOperation, inventoryClient inventory.Client, keep func(*hanav1.MappingID) bool, forget func(*hanav1.MappingID)) error {
+	for _, operation := range append([]hanav1.InventoryOperation(nil), *operations...) {
+		mappingID := normalizeMappingID(&operation.MappingID)
+		if keep(mappingID) {
+			continue
+		}
+
+		if err := deleteInventoryMapping(ctx, inventoryClient, mappingID); err != nil {
+			return err
+		}
+		if err := patchStatus(ctx, c, obj, func() {
+			*operations = removeOperations(*operations, mappingID)
+			forget(mappingID)
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeOperations returns the operations which do not concern mappingID.
+func removeOperations(operations []hanav1.InventoryOperation, mappingID *hanav1.MappingID) []hanav1.InventoryOperation {
+	var kept []hanav1.InventoryOperation
+	for _, operation := range operations {
+		if *normalizeMappingID(&operation.MappingID) != *mappingID {
+			kept = append(kept, operation)
+		}
+	}
+	return kept
+}
+
+func getAdminAPIAccessBinding(ctx context.Context, c client.Client, secretName types.NamespacedName) (inventory.Binding, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, secretName, secret); err != nil {
+		return inventory.Binding{}, err
+	}
+	return inventory.BindingFromSecretData(secret.Data)
+}
+
+// resolveAdminAPIAccessBinding reads the admin API access binding for an
+// object in namespace, either from the ready HANAAdminCredentials named
+// adminCredentials or from the secret if the namespace is permitted to
+// reference it. The binding of credentials is taken from bindings if it holds
+// the binding of their current version.
+func resolveAdminAPIAccessBinding(ctx context.Context, c client.Client, bindings *BindingStore, namespace string, secret *hanav1.NamespacedName, adminCredentials string) (inventory.Binding, error) {
+	if len(adminCredentials) > 0 {
+		credentials := &hanav1.HANAAdminCredentials{}
+		if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: adminCredentials}, credentials); err != nil {
+			return inventory.Binding{}, err
+		}
+
+		ready := meta.FindStatusCondition(credentials.Status.Conditions, conditionTypeReady)
+		if ready == nil || ready.Status != metav1.ConditionTrue || ready.ObservedGeneration != credentials.Generation {
+			return inventory.Binding{}, &conditionError{
+				reason:  conditionReasonCredentialsNotReady,
+				message: fmt.Sprintf("hanaadmincredentials %s is not ready", credentials.Name),
+			}
+		}
+
+		if binding, ok := bindings.Get(credentials); ok {
+			return binding, nil
+		}
+		binding, _, err := getAdminCredentialsBinding(ctx, c, credentials)
+		return binding, err
+	}
+
+	if secret == nil {
+		return inventory.Binding{}, &conditionError{
+			reason:  conditionReasonInvalidCredentials,
+			message: "neither adminAPIAccessSecret nor adminCredentials is set",
+		}
+	}
+
+	permitted, err := hanav1.AdminAPIAccessSecretPermitted(ctx, c, namespace, *secret)
+	if err != nil {
+		return inventory.Binding{}, err
+	}
+	if !permitted {
+		return inventory.Binding{}, &conditionError{
+			reason:  conditionReasonCredentialsNotPermitted,
+			message: fmt.Sprintf("no hanamappingcredentialgrant in namespace %s permits namespace %s to reference secret %s", secret.Namespace, namespace, secret.Name),
+		}
+	}
+
+	return getAdminAPIAccessBinding(ctx, c, types.NamespacedName{Namespace: secret.Namespace, Name: secret.Name})
+}
+
+// getAdminCredentialsSecretName returns the name of the secret holding the
+// admin API access binding of the credentials. For a ServiceBinding this is
+// the secret the BTP operator writes the binding to.
+func getAdminCredentialsSecretName(ctx context.Context, c client.Client, credentials *hanav1.HANAAdminCredentials) (string, error) {
+	if credentials.Spec.SecretRef != nil {
+		return credentials.Spec.SecretRef.Name, nil
+	}
+	if credentials.Spec.ServiceBindingRef == nil {
+		return "", &conditionError{
+			reason:  conditionReasonInvalidCredentials,
+			message: "neither secretRef nor serviceBindingRef is set",
+		}
+	}
+
+	serviceBinding := &unstructured.Unstructured{}
+	serviceBinding.SetGroupVersionKind(serviceBindingGVK)
+	if err := c.Get(ctx, types.NamespacedName{Namespace: credentials.Namespace, Name: credentials.Spec.ServiceBindingRef.Name}, serviceBinding); err != nil {
+		return "", err
+	}
+
+	secretName, _, err := unstructured.NestedString(serviceBinding.Object, "spec", "secretName")
+	if err != nil {
+		return "", err
+	}
+	if len(secretName) == 0 {
+		secretName = serviceBinding.GetName()
+	}
+	return secretName, nil
+}
+
+// getAdminCredentialsBinding reads the admin API access binding of the
+// credentials and applies their CA bundle and proxy settings.
+func getAdminCredentialsBinding(ctx context.Context, c client.Client, credentials *hanav1.HANAAdminCredentials) (inventory.Binding, string, error) {
+	secretName, err := getAdminCredentialsSecretName(ctx, c, credentials)
+	if err != nil {
+		return inventory.Binding{}, "", err
+	}
+
+	binding, err := getAdminAPIAccessBinding(ctx, c, types.NamespacedName{Namespace: credentials.Namespace, Name: secretName})
+	if err != nil {
+		return inventory.Binding{}, secretName, err
+	}
+
+	if len(credentials.Spec.CABundle) > 0 {
+		binding.CABundle = []byte(credentials.Spec.CABundle)
+	}
+	binding.ProxyURL = credentials.Spec.ProxyURL
+	return binding, secretName, nil
+}
+
+func mappingPlatform(platform string) string {
+	if len(platform) == 0 {
+		return hanav1.PlatformKubernetes
+	}
+	return platform
+}
+
+// annotationChangedPredicate passes updates which change one of the given
+// annotations.
+func annotationChangedPredicate(keys ...string) predicate.Funcs {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			for _, key := range keys {
+				if e.ObjectOld.GetAnnotations()[key] != e.ObjectNew.GetAnnotations()[key] {
+					return true
+				}
+			}
+			return false
+		},
+	}
+}
+
+// normalizeMappingID defaults the platform of mapping IDs which were recorded
+// before the platform was part of the status.
+func normalizeMappingID(mappingID *hanav1.MappingID) *hanav1.MappingID {
+	if mappingID == nil {
+		return nil
+	}
+	normalized := *mappingID
+	normalized.Platform = mappingPlatform(normalized.Platform)
+	return &normalized
+}
+
+// failureReason returns the reason a failed sync is reported with in the
+// Ready condition.
+func failureReason(err error) string {
+	if err == inventory.ErrCircuitOpen {
+		return conditionReasonInventoryUnavailable
+	}
+	if condErr, ok := err.(*conditionError); ok {
+		return condErr.reason
+	}
+	return conditionReasonFailed
+}
+
+// conditionError is a sync error which is reported with a specific reason in
+// the Ready condition.
+type conditionError struct {
+	reason  string
+	message string
+}
+
+func (e *conditionError) Error() string {
+	return e.message
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	toolscache "k8s.io/client-go/tools/cache"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// TTLClient reads objects of the configured types, e.g. secrets and config
+// maps, from the API server and keeps them for TTL instead of holding all of
+// them in the informer cache. A cached object is dropped as soon as the
+// metadata-only watch registered by SetupWithManager reports a new resource
+// version. All other reads and writes are delegated to the embedded client.
+type TTLClient struct {
+	client.Client
+
+	reader client.Reader
+	ttl    time.Duration
+	types  map[schema.GroupVersionKind]reflect.Type
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[ttlKey]ttlEntry
+}
+
+type ttlKey struct {
+	gvk schema.GroupVersionKind
+	key types.NamespacedName
+}
+
+type ttlEntry struct {
+	obj     client.Object
+	expires time.Time
+}
+
+// NewTTLClient returns a TTLClient reading objs through reader. A TTL which
+// is not positive reads every object from the API server.
+func NewTTLClient(c client.Client, reader client.Reader, ttl time.Duration, objs ...client.Object) (*TTLClient, error) {
+	ttlClient := &TTLClient{
+		Client:  c,
+		reader:  reader,
+		ttl:     ttl,
+		types:   map[schema.GroupVersionKind]reflect.Type{},
+		now:     time.Now,
+		entries: map[ttlKey]ttlEntry{},
+	}
+	for _, obj := range objs {
+		gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+		if err != nil {
+			return nil, err
+		}
+		ttlClient.types[gvk] = reflect.TypeOf(obj)
+	}
+	return ttlClient, nil
+}
+
+// SetupWithManager watches the metadata of the configured types to drop
+// changed and deleted objects from the cache.
+func (c *TTLClient) SetupWithManager(mgr ctrl.Manager) error {
+	for gvk := range c.types {
+		obj := &metav1.PartialObjectMetadata{}
+		obj.SetGroupVersionKind(gvk)
+		informer, err := mgr.GetCache().GetInformer(context.Background(), obj)
+		if err != nil {
+			return err
+		}
+
+		gvk := gvk
+		if _, err := informer.AddEventHandler(toolscache.ResourceEventHandlerFuncs{
+			UpdateFunc: func(_, obj interface{}) {
+				c.invalidate(gvk, obj, false)
+			},
+			DeleteFunc: func(obj interface{}) {
+				if tombstone, ok := obj.(toolscache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				c.invalidate(gvk, obj, true)
+			},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Get reads objects of the configured types from the cache if they have not
+// expired, or else from the API server.
+func (c *TTLClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	gvk, err := apiutil.GVKForObject(obj, c.Scheme())
+	if err != nil {
+		return err
+	}
+	if t, ok := c.types[gvk]; !ok || t != reflect.TypeOf(obj) {
+		return c.Client.Get(ctx, key, obj, opts...)
+	}
+
+	k := ttlKey{gvk: gvk, key: key}
+	c.mu.Lock()
+	entry, ok := c.entries[k]
+	c.mu.Unlock()
+	if ok && c.now().Before(entry.expires) {
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(entry.obj.DeepCopyObject()).Elem())
+		return nil
+	}
+
+	if err := c.reader.Get(ctx, key, obj, opts...); err != nil {
+		if errors.IsNotFound(err) {
+			c.mu.Lock()
+			delete(c.entries, k)
+			c.mu.Unlock()
+		}
+		return err
+	}
+	if c.ttl > 0 {
+		c.mu.Lock()
+		c.entries[k] = ttlEntry{obj: obj.DeepCopyObject().(client.Object), expires: c.now().Add(c.ttl)}
+		c.mu.Unlock()
+	}
+	return nil
+}
+
+// APIReader returns the reader which reads from the API server directly.
+func (c *TTLClient) APIReader() client.Reader {
+	return c.reader
+}
+
+// invalidate drops the cached object if it was deleted or its resource
+// version differs from the one of obj.
+func (c *TTLClient) invalidate(gvk schema.GroupVersionKind, obj interface{}, deleted bool) {
+	object, ok := obj.(client.Object)
+	if !ok {
+		return
+	}
+
+	k := ttlKey{gvk: gvk, key: client.ObjectKeyFromObject(object)}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[k]; ok && (deleted || entry.obj.GetResourceVersion() != object.GetResourceVersion()) {
+		delete(c.entries, k)
+	}
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2024.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("TTLClient", func() {
+	const secretName = "test-ttl-secret"
+
+	var (
+		ctx       context.Context
+		ttlClient *TTLClient
+		now       time.Time
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		now = time.Now()
+
+		var err error
+		ttlClient, err = NewTTLClient(k8sClient, k8sClient, time.Minute, &corev1.Secret{})
+		Expect(err).NotTo(HaveOccurred())
+		ttlClient.now = func() time.Time { return now }
+
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: testNamespace, Name: secretName},
+			StringData: map[string]string{"key": "old"},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		secret := &corev1.Secret{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret); err == nil {
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		}
+	})
+
+	updateSecret := func() *corev1.Secret {
+		secret := &corev1.Secret{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret)).To(Succeed())
+		secret.Data["key"] = []byte("new")
+		Expect(k8sClient.Update(ctx, secret)).To(Succeed())
+		return secret
+	}
+
+	It("should cache secrets until they expire", func() {
+		secret := &corev1.Secret{}
+		Expect(ttlClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("key", []byte("old")))
+
+		updateSecret()
+		secret = &corev1.Secret{}
+		Expect(ttlClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("key", []byte("old")))
+
+		now = now.Add(2 * time.Minute)
+		secret = &corev1.Secret{}
+		Expect(ttlClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("key", []byte("new")))
+	})
+
+	It("should drop secrets with a new resource version", func() {
+		secret := &corev1.Secret{}
+		Expect(ttlClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret)).To(Succeed())
+
+		ttlClient.invalidate(corev1.SchemeGroupVersion.WithKind("Secret"), secret, false)
+		updated := updateSecret()
+		secret = &corev1.Secret{}
+		Expect(ttlClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("key", []byte("old")))
+
+		ttlClient.invalidate(corev1.SchemeGroupVersion.WithKind("Secret"), updated, false)
+		secret = &corev1.Secret{}
+		Expect(ttlClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: secretName}, secret)).To(Succeed())
+		Expect(secret.Data).To(HaveKeyWithValue("key", []byte("new")))
+	})
+
+	It("should not cache missing secrets", func() {
+		secret := &corev1.Secret{}
+		Expect(ttlClient.Get(ctx, types.NamespacedName{Namespace: testNamespace, Name: "missing"}, secret)).NotTo(Succeed())
+		Expect(ttlClient.entries).To(BeEmpty())
+	})
+})
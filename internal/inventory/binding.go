@@ -0,0 +1,130 @@
+package inventory
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type Binding struct {
+	BaseURL string
+	UAA     BindingUAA
+
+	// CABundle holds additional PEM encoded CA certificates trusted for the
+	// token and the inventory API.
+	CABundle []byte
+	// ProxyURL is the URL of an HTTP proxy the requests are sent through.
+	ProxyURL string
+}
+
+type BindingUAA struct {
+	URL          string `json:"url,omitempty"`
+	ClientID     string `json:"clientid,omitempty"`
+	ClientSecret string `json:"clientsecret,omitempty"`
+}
+
+// BindingFromSecretData reads a binding from the data of an admin API access
+// secret, which holds the base URL in baseurl and the UAA credentials as JSON
+// in uaa.
+func BindingFromSecretData(data map[string][]byte) (Binding, error) {
+	uaa := BindingUAA{}
+	if err := json.Unmarshal(data["uaa"], &uaa); err != nil {
+		return Binding{}, err
+	}
+
+	return Binding{
+		BaseURL: string(data["baseurl"]),
+		UAA:     uaa,
+	}, nil
+}
+
+// ValidateBinding checks that a token can be fetched with the binding.
+func ValidateBinding(ctx context.Context, binding Binding) error {
+	ctx, err := binding.httpContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = binding.tokenConfig().Token(ctx)
+	return err
+}
+
+func (b Binding) tokenConfig() *clientcredentials.Config {
+	return &clientcredentials.Config{
+		TokenURL:     b.UAA.URL + "/oauth/token?grant_type=client_credentials",
+		ClientID:     b.UAA.ClientID,
+		ClientSecret: b.UAA.ClientSecret,
+	}
+}
+
+// httpClients holds the HTTP clients by CA bundle and proxy, so that all
+// requests of a binding share one transport and its connection pool.
+var httpClients = struct {
+	mu      sync.Mutex
+	clients map[string]*http.Client
+}{clients: map[string]*http.Client{}}
+
+// httpContext returns a context carrying an HTTP client which trusts the CA
+// bundle and uses the proxy of the binding. The context is returned unchanged
+// if neither is set.
+func (b Binding) httpContext(ctx context.Context) (context.Context, error) {
+	if len(b.CABundle) == 0 && len(b.ProxyURL) == 0 {
+		return ctx, nil
+	}
+
+	httpClient, err := b.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, httpClient), nil
+}
+
+func (b Binding) httpClient() (*http.Client, error) {
+	caBundleHash := sha256.Sum256(b.CABundle)
+	key := hex.EncodeToString(caBundleHash[:]) + "|" + b.ProxyURL
+
+	httpClients.mu.Lock()
+	defer httpClients.mu.Unlock()
+
+	if httpClient, ok := httpClients.clients[key]; ok {
+		return httpClient, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if len(b.CABundle) > 0 {
+		rootCAs, err := x509.SystemCertPool()
+		if err != nil {
+			rootCAs = x509.NewCertPool()
+		}
+		if !rootCAs.AppendCertsFromPEM(b.CABundle) {
+			return nil, errors.New("failed to parse CA bundle")
+		}
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    rootCAs,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	if len(b.ProxyURL) > 0 {
+		proxyURL, err := url.Parse(b.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	httpClient := &http.Client{Transport: transport}
+	httpClients.clients[key] = httpClient
+	return httpClient, nil
+}
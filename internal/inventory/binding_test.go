@@ -0,0 +1,82 @@
+package inventory
+
+import (
+	"context"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Binding", func() {
+	var (
+		server  *httptest.Server
+		binding Binding
+	)
+
+	BeforeEach(func() {
+		mux := http.NewServeMux()
+		mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"test-token","token_type":"bearer","expires_in":3600}`)
+		})
+		server = httptest.NewTLSServer(mux)
+
+		binding = Binding{
+			UAA: BindingUAA{URL: server.URL, ClientID: "test-clientid", ClientSecret: "test-clientsecret"},
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should fetch a token with a trusted CA bundle", func() {
+		binding.CABundle = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		Expect(ValidateBinding(context.Background(), binding)).To(Succeed())
+	})
+
+	It("should fail to fetch a token from an untrusted server", func() {
+		Expect(ValidateBinding(context.Background(), binding)).NotTo(Succeed())
+	})
+
+	It("should reuse the HTTP client of a CA bundle", func() {
+		binding.CABundle = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+		first, err := binding.httpClient()
+		Expect(err).NotTo(HaveOccurred())
+		second, err := binding.httpClient()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(second).To(BeIdenticalTo(first))
+
+		binding.ProxyURL = "http://proxy.example.com:3128"
+		proxied, err := binding.httpClient()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proxied).NotTo(BeIdenticalTo(first))
+	})
+
+	It("should fail with an invalid CA bundle", func() {
+		binding.CABundle = []byte("invalid")
+
+		Expect(ValidateBinding(context.Background(), binding)).To(MatchError("failed to parse CA bundle"))
+	})
+
+	It("should read a binding from the data of a secret", func() {
+		binding, err := BindingFromSecretData(map[string][]byte{
+			"baseurl": []byte("api.example.com"),
+			"uaa":     []byte(`{"url":"https://uaa.example.com","clientid":"test-clientid","clientsecret":"test-clientsecret"}`),
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(binding.BaseURL).To(Equal("api.example.com"))
+		Expect(binding.UAA).To(Equal(BindingUAA{URL: "https://uaa.example.com", ClientID: "test-clientid", ClientSecret: "test-clientsecret"}))
+	})
+
+	It("should fail to read a binding without uaa credentials", func() {
+		_, err := BindingFromSecretData(map[string][]byte{"baseurl": []byte("api.example.com")})
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,222 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const ErrCircuitOpen = inventoryError("inventory API unavailable, circuit breaker open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitHalfOpen
+	circuitOpen
+)
+
+// CircuitBreakerClientFactory hands out clients which share one circuit
+// breaker per binding. The circuit opens after failureThreshold consecutive
+// failures and short-circuits all calls with ErrCircuitOpen until cooldown has
+// passed. Afterwards a single probe call is let through (half-open), which
+// either closes the circuit again or reopens it.
+type CircuitBreakerClientFactory struct {
+	newClient        func(binding Binding) Client
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+// NewCircuitBreakerClientFactory wraps newClient with a per binding circuit
+// breaker. A failureThreshold <= 0 disables the circuit breaker.
+func NewCircuitBreakerClientFactory(newClient func(binding Binding) Client, failureThreshold int, cooldown time.Duration) *CircuitBreakerClientFactory {
+	return &CircuitBreakerClientFactory{
+		newClient:        newClient,
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+		breakers:         make(map[string]*circuitBreaker),
+	}
+}
+
+// SetFailureThreshold changes the settings of all current and future circuit
+// breakers. Open circuits stay open until the new cooldown has passed.
+func (f *CircuitBreakerClientFactory) SetFailureThreshold(failureThreshold int, cooldown time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.failureThreshold, f.cooldown = failureThreshold, cooldown
+	for _, breaker := range f.breakers {
+		breaker.mu.Lock()
+		breaker.failureThreshold, breaker.cooldown = failureThreshold, cooldown
+		breaker.mu.Unlock()
+	}
+}
+
+func (f *CircuitBreakerClientFactory) NewClient(binding Binding) Client {
+	breaker := f.breakerFor(binding)
+	if breaker == nil {
+		return f.newClient(binding)
+	}
+
+	return &circuitBreakerClient{
+		client:  f.newClient(binding),
+		breaker: breaker,
+	}
+}
+
+// breakerFor returns the circuit breaker of the binding, or nil if the circuit
+// breaker is disabled.
+func (f *CircuitBreakerClientFactory) breakerFor(binding Binding) *circuitBreaker {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failureThreshold <= 0 {
+		return nil
+	}
+
+	key := bindingKey(binding)
+	breaker, ok := f.breakers[key]
+	if !ok {
+		breaker = &circuitBreaker{
+			failureThreshold: f.failureThreshold,
+			cooldown:         f.cooldown,
+			now:              f.now,
+			binding:          binding,
+		}
+		breaker.recordState()
+		f.breakers[key] = breaker
+	}
+	return breaker
+}
+
+type circuitBreaker struct {
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+	binding          Binding
+
+	mu                  sync.Mutex
+	state               circuitState
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+}
+
+func (b *circuitBreaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case circuitOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return ErrCircuitOpen
+		}
+		b.state = circuitHalfOpen
+		b.probing = true
+		b.recordState()
+		return nil
+	case circuitHalfOpen:
+		if b.probing {
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *circuitBreaker) done(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	// A canceled call says nothing about the inventory API, so it only
+	// releases the probe and leaves the state unchanged.
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	if !isFailure(err) {
+		b.consecutiveFailures = 0
+		if b.state != circuitClosed {
+			b.state = circuitClosed
+			b.recordState()
+		}
+		return
+	}
+
+	b.consecutiveFailures++
+	if b.state == circuitHalfOpen || b.consecutiveFailures >= b.failureThreshold {
+		b.state = circuitOpen
+		b.openedAt = b.now()
+		b.recordState()
+	}
+}
+
+func (b *circuitBreaker) recordState() {
+	circuitBreakerState.WithLabelValues(b.binding.BaseURL, b.binding.UAA.ClientID).Set(float64(b.state))
+}
+
+// isFailure reports whether err indicates that the inventory API is
+// unavailable. Errors caused by the request itself don't count as failures.
+func isFailure(err error) bool {
+	if err == nil || err == ErrMappingNotFound || err == ErrMappingAlreadyExists {
+		return false
+	}
+
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+	return true
+}
+
+type circuitBreakerClient struct {
+	client  Client
+	breaker *circuitBreaker
+}
+
+func (c *circuitBreakerClient) ListMappings(ctx context.Context, serviceInstanceID string) ([]Mapping, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	mappings, err := c.client.ListMappings(ctx, serviceInstanceID)
+	c.breaker.done(err)
+	return mappings, err
+}
+
+func (c *circuitBreakerClient) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*Mapping, error) {
+	if err := c.breaker.allow(); err != nil {
+		return nil, err
+	}
+	mapping, err := c.client.GetMapping(ctx, serviceInstanceID, platform, primaryID, secondaryID)
+	c.breaker.done(err)
+	return mapping, err
+}
+
+func (c *circuitBreakerClient) CreateMapping(ctx context.Context, serviceInstanceID string, mapping Mapping) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err := c.client.CreateMapping(ctx, serviceInstanceID, mapping)
+	c.breaker.done(err)
+	return err
+}
+
+func (c *circuitBreakerClient) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
+	if err := c.breaker.allow(); err != nil {
+		return err
+	}
+	err := c.client.DeleteMapping(ctx, serviceInstanceID, primaryID, secondaryID)
+	c.breaker.done(err)
+	return err
+}
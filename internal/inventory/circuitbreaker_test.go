@@ -0,0 +1,116 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CircuitBreakerClientFactory", func() {
+	var (
+		ctx     context.Context
+		stub    *clientStub
+		now     time.Time
+		factory *CircuitBreakerClientFactory
+		binding Binding
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		stub = &clientStub{err: fmt.Errorf("connection refused")}
+		now = time.Now()
+		factory = NewCircuitBreakerClientFactory(func(binding Binding) Client { return stub }, 3, time.Minute)
+		factory.now = func() time.Time { return now }
+		binding = Binding{BaseURL: "test-baseurl", UAA: BindingUAA{ClientID: "test-clientid"}}
+	})
+
+	It("should open the circuit after consecutive failures", func() {
+		for i := 0; i < 3; i++ {
+			Expect(factory.NewClient(binding).CreateMapping(ctx, "id", Mapping{})).NotTo(MatchError(ErrCircuitOpen))
+		}
+
+		Expect(factory.NewClient(binding).CreateMapping(ctx, "id", Mapping{})).To(MatchError(ErrCircuitOpen))
+		Expect(stub.calls).To(Equal(3))
+	})
+
+	It("should not count request errors as failures", func() {
+		stub.err = &StatusError{Operation: "create mapping", StatusCode: http.StatusBadRequest}
+		for i := 0; i < 5; i++ {
+			Expect(factory.NewClient(binding).CreateMapping(ctx, "id", Mapping{})).NotTo(MatchError(ErrCircuitOpen))
+		}
+		Expect(stub.calls).To(Equal(5))
+	})
+
+	It("should half-open after the cooldown and close on success", func() {
+		client := factory.NewClient(binding)
+		for i := 0; i < 3; i++ {
+			_ = client.DeleteMapping(ctx, "id", "p", "s")
+		}
+		Expect(client.DeleteMapping(ctx, "id", "p", "s")).To(MatchError(ErrCircuitOpen))
+
+		now = now.Add(time.Minute)
+		stub.err = nil
+		Expect(client.DeleteMapping(ctx, "id", "p", "s")).To(Succeed())
+		Expect(client.DeleteMapping(ctx, "id", "p", "s")).To(Succeed())
+		Expect(stub.calls).To(Equal(5))
+	})
+
+	It("should reopen the circuit when the probe fails", func() {
+		client := factory.NewClient(binding)
+		for i := 0; i < 3; i++ {
+			_, _ = client.ListMappings(ctx, "id")
+		}
+
+		now = now.Add(time.Minute)
+		_, err := client.ListMappings(ctx, "id")
+		Expect(err).NotTo(MatchError(ErrCircuitOpen))
+
+		_, err = client.ListMappings(ctx, "id")
+		Expect(err).To(MatchError(ErrCircuitOpen))
+		Expect(stub.calls).To(Equal(4))
+	})
+
+	It("should keep the circuit half-open when the probe is canceled", func() {
+		client := factory.NewClient(binding)
+		for i := 0; i < 3; i++ {
+			_, _ = client.ListMappings(ctx, "id")
+		}
+
+		now = now.Add(time.Minute)
+		stub.err = context.Canceled
+		_, err := client.ListMappings(ctx, "id")
+		Expect(err).To(MatchError(context.Canceled))
+
+		stub.err = fmt.Errorf("connection refused")
+		_, err = client.ListMappings(ctx, "id")
+		Expect(err).NotTo(MatchError(ErrCircuitOpen))
+		_, err = client.ListMappings(ctx, "id")
+		Expect(err).To(MatchError(ErrCircuitOpen))
+		Expect(stub.calls).To(Equal(5))
+	})
+
+	It("should keep the circuits of different bindings apart", func() {
+		for i := 0; i < 3; i++ {
+			_ = factory.NewClient(binding).CreateMapping(ctx, "id", Mapping{})
+		}
+
+		stub.err = nil
+		Expect(factory.NewClient(Binding{BaseURL: "other-baseurl"}).CreateMapping(ctx, "id", Mapping{})).To(Succeed())
+	})
+
+	It("should apply a changed failure threshold to existing circuits", func() {
+		client := factory.NewClient(binding)
+		factory.SetFailureThreshold(1, time.Minute)
+
+		_ = client.CreateMapping(ctx, "id", Mapping{})
+		Expect(client.CreateMapping(ctx, "id", Mapping{})).To(MatchError(ErrCircuitOpen))
+
+		factory.SetFailureThreshold(0, time.Minute)
+		Expect(factory.NewClient(binding).CreateMapping(ctx, "id", Mapping{})).NotTo(MatchError(ErrCircuitOpen))
+		Expect(stub.calls).To(Equal(2))
+	})
+})
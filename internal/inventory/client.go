@@ -6,8 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-
-	"golang.org/x/oauth2/clientcredentials"
 )
 
 const (
@@ -17,6 +15,7 @@ const (
 
 type Client interface {
 	ListMappings(ctx context.Context, serviceInstanceID string) ([]Mapping, error)
+	GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*Mapping, error)
 	CreateMapping(ctx context.Context, serviceInstanceID string, mapping Mapping) error
 	DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error
 }
@@ -28,22 +27,27 @@ type Mapping struct {
 	IsDefault   bool   `json:"isDefault,omitempty"`
 }
 
+// defaultPlatform is the platform of mappings which the inventory API returns
+// without one.
+const defaultPlatform = "kubernetes"
+
+// SamePlatform reports whether two platforms are equal, treating an empty
+// platform as kubernetes.
+func SamePlatform(a, b string) bool {
+	if len(a) == 0 {
+		a = defaultPlatform
+	}
+	if len(b) == 0 {
+		b = defaultPlatform
+	}
+	return a == b
+}
+
 const (
 	ErrMappingAlreadyExists = inventoryError("mapping already exists")
 	ErrMappingNotFound      = inventoryError("mapping not found")
 )
 
-type Binding struct {
-	BaseURL string
-	UAA     BindingUAA
-}
-
-type BindingUAA struct {
-	URL          string `json:"url,omitempty"`
-	ClientID     string `json:"clientid,omitempty"`
-	ClientSecret string `json:"clientsecret,omitempty"`
-}
-
 type inventoryClient struct {
 	Binding Binding
 }
@@ -81,7 +85,24 @@ func (c *inventoryClient) ListMappings(ctx context.Context, serviceInstanceID st
 		return respBody.Mappings, nil
 	}
 
-	return nil, fmt.Errorf("failed to list mappings, HTTP %d", resp.StatusCode)
+	return nil, &StatusError{Operation: "list mappings", StatusCode: resp.StatusCode}
+}
+
+// GetMapping returns the mapping of the given platform and IDs, as mappings
+// of different platforms may share their IDs.
+func (c *inventoryClient) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*Mapping, error) {
+	mappings, err := c.ListMappings(ctx, serviceInstanceID)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range mappings {
+		if SamePlatform(mappings[i].Platform, platform) && mappings[i].PrimaryID == primaryID && mappings[i].SecondaryID == secondaryID {
+			return &mappings[i], nil
+		}
+	}
+
+	return nil, ErrMappingNotFound
 }
 
 func (c *inventoryClient) CreateMapping(ctx context.Context, serviceInstanceID string, mapping Mapping) error {
@@ -110,7 +131,7 @@ func (c *inventoryClient) CreateMapping(ctx context.Context, serviceInstanceID s
 		return ErrMappingAlreadyExists
 	}
 
-	return fmt.Errorf("failed to create mapping, HTTP %d", resp.StatusCode)
+	return &StatusError{Operation: "create mapping", StatusCode: resp.StatusCode}
 }
 
 func (c *inventoryClient) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
@@ -139,20 +160,28 @@ func (c *inventoryClient) DeleteMapping(ctx context.Context, serviceInstanceID s
 		return ErrMappingNotFound
 	}
 
-	return fmt.Errorf("failed to delete mapping, HTTP %d", resp.StatusCode)
+	return &StatusError{Operation: "delete mapping", StatusCode: resp.StatusCode}
 }
 
 func (c *inventoryClient) doAuthRequest(ctx context.Context, req *http.Request) (*http.Response, error) {
-	config := clientcredentials.Config{
-		TokenURL:     c.Binding.UAA.URL + "/oauth/token?grant_type=client_credentials",
-		ClientID:     c.Binding.UAA.ClientID,
-		ClientSecret: c.Binding.UAA.ClientSecret,
+	ctx, err := c.Binding.httpContext(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	client := config.Client(ctx)
+	client := c.Binding.tokenConfig().Client(ctx)
 	return client.Do(req)
 }
 
+type StatusError struct {
+	Operation  string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("failed to %s, HTTP %d", e.Operation, e.StatusCode)
+}
+
 type inventoryError string
 
 func (e inventoryError) Error() string {
@@ -0,0 +1,84 @@
+package inventory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/oauth2"
+)
+
+var _ = Describe("Client", func() {
+	var (
+		ctx      context.Context
+		server   *httptest.Server
+		client   Client
+		mappings []Mapping
+	)
+
+	BeforeEach(func() {
+		mappings = []Mapping{
+			{Platform: "kubernetes", PrimaryID: "cluster-a", SecondaryID: "namespace-a"},
+			{Platform: "kubernetes", PrimaryID: "cluster-a", SecondaryID: "namespace-b", IsDefault: true},
+			{Platform: "cloudfoundry", PrimaryID: "org-a", SecondaryID: "space-a"},
+			{PrimaryID: "org-a", SecondaryID: "space-a", IsDefault: true},
+		}
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/oauth/token", func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"test-token","token_type":"bearer","expires_in":3600}`)
+		})
+		mux.HandleFunc(fmt.Sprintf(mappingsPath, "test-serviceinstanceid"), func(w http.ResponseWriter, r *http.Request) {
+			Expect(r.Header.Get("Authorization")).To(Equal("Bearer test-token"))
+			w.Header().Set("Content-Type", "application/json")
+			Expect(json.NewEncoder(w).Encode(map[string][]Mapping{"mappings": mappings})).To(Succeed())
+		})
+		server = httptest.NewTLSServer(mux)
+
+		ctx = context.WithValue(context.Background(), oauth2.HTTPClient, server.Client())
+		client = NewClient(Binding{
+			BaseURL: strings.TrimPrefix(server.URL, "https://"),
+			UAA:     BindingUAA{URL: server.URL, ClientID: "test-clientid", ClientSecret: "test-clientsecret"},
+		})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should list the mappings of a service instance", func() {
+		Expect(client.ListMappings(ctx, "test-serviceinstanceid")).To(Equal(mappings))
+	})
+
+	It("should get a single mapping", func() {
+		mapping, err := client.GetMapping(ctx, "test-serviceinstanceid", "kubernetes", "cluster-a", "namespace-b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*mapping).To(Equal(mappings[1]))
+	})
+
+	It("should tell mappings of different platforms apart", func() {
+		mapping, err := client.GetMapping(ctx, "test-serviceinstanceid", "cloudfoundry", "org-a", "space-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*mapping).To(Equal(mappings[2]))
+
+		mapping, err = client.GetMapping(ctx, "test-serviceinstanceid", "kubernetes", "org-a", "space-a")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(*mapping).To(Equal(mappings[3]))
+	})
+
+	It("should fail to get a missing mapping", func() {
+		_, err := client.GetMapping(ctx, "test-serviceinstanceid", "kubernetes", "cluster-a", "namespace-c")
+		Expect(err).To(MatchError(ErrMappingNotFound))
+	})
+
+	It("should fail to list the mappings of an unknown service instance", func() {
+		_, err := client.ListMappings(ctx, "unknown")
+		Expect(err).To(MatchError(&StatusError{Operation: "list mappings", StatusCode: http.StatusNotFound}))
+	})
+})
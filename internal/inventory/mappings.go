@@ -0,0 +1,88 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+)
+
+// MappingRemovedError is returned by EnsureMapping if it deleted a mapping to
+// change its isDefault flag, but could neither recreate nor restore it.
+type MappingRemovedError struct {
+	Err error
+}
+
+func (e *MappingRemovedError) Error() string {
+	return "mapping was deleted to change its default flag and could not be recreated: " + e.Err.Error()
+}
+
+func (e *MappingRemovedError) Unwrap() error {
+	return e.Err
+}
+
+// IsMappingRemoved reports whether err is a MappingRemovedError.
+func IsMappingRemoved(err error) bool {
+	var removedErr *MappingRemovedError
+	return errors.As(err, &removedErr)
+}
+
+// EnsureMapping creates the mapping unless it already exists. An existing
+// mapping is only accepted if it is owned by the caller already, it is updated
+// if its isDefault flag differs from the desired one.
+func EnsureMapping(ctx context.Context, c Client, serviceInstanceID string, mapping Mapping, owned bool) error {
+	existingMapping, err := c.GetMapping(ctx, serviceInstanceID, mapping.Platform, mapping.PrimaryID, mapping.SecondaryID)
+	if err == ErrMappingNotFound {
+		err = c.CreateMapping(ctx, serviceInstanceID, mapping)
+		if err != nil && (!owned || err != ErrMappingAlreadyExists) {
+			return err
+		}
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if !owned {
+		return ErrMappingAlreadyExists
+	}
+	if existingMapping.IsDefault == mapping.IsDefault {
+		return nil
+	}
+	existing := *existingMapping
+	existing.Platform = mapping.Platform
+	return updateMapping(ctx, c, serviceInstanceID, existing, mapping)
+}
+
+// updateMapping changes the isDefault flag of an existing mapping. The API
+// cannot update a mapping in place, so it is deleted and recreated, and the
+// existing mapping is restored if the recreation fails, so that the target is
+// not left unmapped.
+func updateMapping(ctx context.Context, c Client, serviceInstanceID string, existing, mapping Mapping) error {
+	if err := c.DeleteMapping(ctx, serviceInstanceID, mapping.PrimaryID, mapping.SecondaryID); err != nil && err != ErrMappingNotFound {
+		return err
+	}
+	createErr := c.CreateMapping(ctx, serviceInstanceID, mapping)
+	if createErr == nil || createErr == ErrMappingAlreadyExists {
+		return nil
+	}
+	if err := c.CreateMapping(ctx, serviceInstanceID, existing); err != nil && err != ErrMappingAlreadyExists {
+		return &MappingRemovedError{Err: createErr}
+	}
+	return createErr
+}
+
+// RemoveMapping deletes the mapping if it still exists on the given platform.
+func RemoveMapping(ctx context.Context, c Client, serviceInstanceID string, platform, primaryID, secondaryID string) error {
+	_, err := c.GetMapping(ctx, serviceInstanceID, platform, primaryID, secondaryID)
+	if err != nil {
+		if err == ErrMappingNotFound {
+			return nil
+		}
+		return err
+	}
+
+	err = c.DeleteMapping(ctx, serviceInstanceID, primaryID, secondaryID)
+	if err != nil && err != ErrMappingNotFound {
+		return err
+	}
+	return nil
+}
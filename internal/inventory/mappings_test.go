@@ -0,0 +1,136 @@
+package inventory
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mappings", func() {
+	var (
+		ctx     context.Context
+		store   *storeStub
+		mapping Mapping
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		store = &storeStub{mappings: map[string]Mapping{}}
+		mapping = Mapping{Platform: "kubernetes", PrimaryID: "cluster-a", SecondaryID: "namespace-a"}
+	})
+
+	It("should create a missing mapping", func() {
+		Expect(EnsureMapping(ctx, store, "test-serviceinstanceid", mapping, false)).To(Succeed())
+		Expect(store.mappings).To(HaveKeyWithValue("cluster-a/namespace-a", mapping))
+	})
+
+	It("should not take over a mapping which is not owned", func() {
+		store.mappings["cluster-a/namespace-a"] = mapping
+
+		Expect(EnsureMapping(ctx, store, "test-serviceinstanceid", mapping, false)).To(MatchError(ErrMappingAlreadyExists))
+	})
+
+	It("should recreate an owned mapping whose isDefault flag differs", func() {
+		store.mappings["cluster-a/namespace-a"] = mapping
+		mapping.IsDefault = true
+
+		Expect(EnsureMapping(ctx, store, "test-serviceinstanceid", mapping, true)).To(Succeed())
+		Expect(store.mappings["cluster-a/namespace-a"].IsDefault).To(BeTrue())
+		Expect(store.created).To(Equal(1))
+	})
+
+	It("should restore an owned mapping whose recreation fails", func() {
+		store.mappings["cluster-a/namespace-a"] = mapping
+		createErr := &StatusError{Operation: "create mapping", StatusCode: 503}
+		store.createErr = func(m Mapping) error {
+			if m.IsDefault {
+				return createErr
+			}
+			return nil
+		}
+		mapping.IsDefault = true
+
+		Expect(EnsureMapping(ctx, store, "test-serviceinstanceid", mapping, true)).To(MatchError(createErr))
+		Expect(store.mappings).To(HaveKey("cluster-a/namespace-a"))
+		Expect(store.mappings["cluster-a/namespace-a"].IsDefault).To(BeFalse())
+	})
+
+	It("should report an owned mapping which could neither be recreated nor restored", func() {
+		store.mappings["cluster-a/namespace-a"] = mapping
+		createErr := &StatusError{Operation: "create mapping", StatusCode: 503}
+		store.createErr = func(Mapping) error {
+			return createErr
+		}
+		mapping.IsDefault = true
+
+		err := EnsureMapping(ctx, store, "test-serviceinstanceid", mapping, true)
+		Expect(IsMappingRemoved(err)).To(BeTrue())
+		Expect(err).To(MatchError(createErr))
+		Expect(store.mappings).To(BeEmpty())
+	})
+
+	It("should delete an existing mapping", func() {
+		store.mappings["cluster-a/namespace-a"] = mapping
+
+		Expect(RemoveMapping(ctx, store, "test-serviceinstanceid", "kubernetes", "cluster-a", "namespace-a")).To(Succeed())
+		Expect(store.mappings).To(BeEmpty())
+	})
+
+	It("should not delete a mapping of another platform", func() {
+		mapping.Platform = "cloudfoundry"
+		store.mappings["cluster-a/namespace-a"] = mapping
+
+		Expect(RemoveMapping(ctx, store, "test-serviceinstanceid", "kubernetes", "cluster-a", "namespace-a")).To(Succeed())
+		Expect(store.mappings).To(HaveLen(1))
+	})
+
+	It("should skip deleting a missing mapping", func() {
+		Expect(RemoveMapping(ctx, store, "test-serviceinstanceid", "kubernetes", "cluster-a", "namespace-a")).To(Succeed())
+	})
+})
+
+// storeStub keeps the mappings of a single service instance.
+type storeStub struct {
+	mappings  map[string]Mapping
+	createErr func(mapping Mapping) error
+	created   int
+}
+
+func (c *storeStub) ListMappings(ctx context.Context, serviceInstanceID string) ([]Mapping, error) {
+	mappings := make([]Mapping, 0, len(c.mappings))
+	for _, mapping := range c.mappings {
+		mappings = append(mappings, mapping)
+	}
+	return mappings, nil
+}
+
+func (c *storeStub) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*Mapping, error) {
+	mapping, ok := c.mappings[primaryID+"/"+secondaryID]
+	if !ok || !SamePlatform(mapping.Platform, platform) {
+		return nil, ErrMappingNotFound
+	}
+	return &mapping, nil
+}
+
+func (c *storeStub) CreateMapping(ctx context.Context, serviceInstanceID string, mapping Mapping) error {
+	if c.createErr != nil {
+		if err := c.createErr(mapping); err != nil {
+			return err
+		}
+	}
+	if _, ok := c.mappings[mapping.PrimaryID+"/"+mapping.SecondaryID]; ok {
+		return ErrMappingAlreadyExists
+	}
+	c.mappings[mapping.PrimaryID+"/"+mapping.SecondaryID] = mapping
+	c.created++
+	return nil
+}
+
+func (c *storeStub) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
+	if _, ok := c.mappings[primaryID+"/"+secondaryID]; !ok {
+		return ErrMappingNotFound
+	}
+	delete(c.mappings, primaryID+"/"+secondaryID)
+	return nil
+}
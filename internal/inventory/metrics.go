@@ -0,0 +1,18 @@
+package inventory
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var circuitBreakerState = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "hana_inventory_circuit_breaker_state",
+		Help: "State of the inventory API circuit breaker per binding (0 = closed, 1 = half-open, 2 = open).",
+	},
+	[]string{"base_url", "client_id"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(circuitBreakerState)
+}
@@ -0,0 +1,108 @@
+package inventory
+
+import (
+	"context"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitedClientFactory hands out clients which share one token bucket per
+// binding, so that all reconciles using the same admin API access binding stay
+// within a common request budget.
+type RateLimitedClientFactory struct {
+	newClient func(binding Binding) Client
+	limit     rate.Limit
+	burst     int
+
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// NewRateLimitedClientFactory wraps newClient with a per binding rate limiter.
+// A qps <= 0 disables rate limiting.
+func NewRateLimitedClientFactory(newClient func(binding Binding) Client, qps float64, burst int) *RateLimitedClientFactory {
+	f := &RateLimitedClientFactory{
+		newClient: newClient,
+		limiters:  make(map[string]*rate.Limiter),
+	}
+	f.SetLimits(qps, burst)
+	return f
+}
+
+// SetLimits changes the limits of all current and future clients.
+func (f *RateLimitedClientFactory) SetLimits(qps float64, burst int) {
+	limit := rate.Limit(qps)
+	if qps <= 0 {
+		limit = rate.Inf
+	}
+	if burst < 1 {
+		burst = 1
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.limit, f.burst = limit, burst
+	for _, limiter := range f.limiters {
+		limiter.SetLimit(limit)
+		limiter.SetBurst(burst)
+	}
+}
+
+func (f *RateLimitedClientFactory) NewClient(binding Binding) Client {
+	return &rateLimitedClient{
+		client:  f.newClient(binding),
+		limiter: f.limiterFor(binding),
+	}
+}
+
+func (f *RateLimitedClientFactory) limiterFor(binding Binding) *rate.Limiter {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := bindingKey(binding)
+	limiter, ok := f.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(f.limit, f.burst)
+		f.limiters[key] = limiter
+	}
+	return limiter
+}
+
+func bindingKey(binding Binding) string {
+	return binding.BaseURL + "|" + binding.UAA.ClientID
+}
+
+type rateLimitedClient struct {
+	client  Client
+	limiter *rate.Limiter
+}
+
+func (c *rateLimitedClient) ListMappings(ctx context.Context, serviceInstanceID string) ([]Mapping, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.client.ListMappings(ctx, serviceInstanceID)
+}
+
+func (c *rateLimitedClient) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*Mapping, error) {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	return c.client.GetMapping(ctx, serviceInstanceID, platform, primaryID, secondaryID)
+}
+
+func (c *rateLimitedClient) CreateMapping(ctx context.Context, serviceInstanceID string, mapping Mapping) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.client.CreateMapping(ctx, serviceInstanceID, mapping)
+}
+
+func (c *rateLimitedClient) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.client.DeleteMapping(ctx, serviceInstanceID, primaryID, secondaryID)
+}
@@ -0,0 +1,57 @@
+package inventory
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("RateLimitedClientFactory", func() {
+	var (
+		stub    *clientStub
+		factory *RateLimitedClientFactory
+	)
+
+	BeforeEach(func() {
+		stub = &clientStub{}
+		factory = NewRateLimitedClientFactory(func(binding Binding) Client { return stub }, 0.001, 1)
+	})
+
+	It("should share the token bucket between clients of the same binding", func() {
+		binding := Binding{BaseURL: "test-baseurl", UAA: BindingUAA{ClientID: "test-clientid"}}
+
+		Expect(factory.NewClient(binding).CreateMapping(context.Background(), "id", Mapping{})).To(Succeed())
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		Expect(factory.NewClient(binding).CreateMapping(ctx, "id", Mapping{})).NotTo(Succeed())
+		Expect(stub.calls).To(Equal(1))
+	})
+
+	It("should not throttle clients of different bindings", func() {
+		Expect(factory.NewClient(Binding{BaseURL: "a"}).CreateMapping(context.Background(), "id", Mapping{})).To(Succeed())
+		Expect(factory.NewClient(Binding{BaseURL: "b"}).CreateMapping(context.Background(), "id", Mapping{})).To(Succeed())
+		Expect(stub.calls).To(Equal(2))
+	})
+
+	It("should not throttle when rate limiting is disabled", func() {
+		factory = NewRateLimitedClientFactory(func(binding Binding) Client { return stub }, 0, 0)
+		for i := 0; i < 10; i++ {
+			Expect(factory.NewClient(Binding{}).DeleteMapping(context.Background(), "id", "p", "s")).To(Succeed())
+		}
+		Expect(stub.calls).To(Equal(10))
+	})
+
+	It("should apply changed limits to existing clients", func() {
+		client := factory.NewClient(Binding{})
+		Expect(client.CreateMapping(context.Background(), "id", Mapping{})).To(Succeed())
+
+		factory.SetLimits(0, 0)
+		for i := 0; i < 10; i++ {
+			Expect(client.CreateMapping(context.Background(), "id", Mapping{})).To(Succeed())
+		}
+		Expect(stub.calls).To(Equal(11))
+	})
+})
@@ -0,0 +1,40 @@
+package inventory
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+func TestInventory(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Inventory Suite")
+}
+
+type clientStub struct {
+	calls int
+	err   error
+}
+
+func (c *clientStub) ListMappings(ctx context.Context, serviceInstanceID string) ([]Mapping, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func (c *clientStub) GetMapping(ctx context.Context, serviceInstanceID string, platform, primaryID, secondaryID string) (*Mapping, error) {
+	c.calls++
+	return nil, c.err
+}
+
+func (c *clientStub) CreateMapping(ctx context.Context, serviceInstanceID string, mapping Mapping) error {
+	c.calls++
+	return c.err
+}
+
+func (c *clientStub) DeleteMapping(ctx context.Context, serviceInstanceID string, primaryID, secondaryID string) error {
+	c.calls++
+	return c.err
+}